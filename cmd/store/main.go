@@ -18,6 +18,9 @@ func main() {
 	m := NewMain()
 	if err := m.Run(os.Args[1:]...); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		if err == query.ErrEmptyResult {
+			os.Exit(2)
+		}
 		os.Exit(1)
 	}
 }
@@ -54,7 +57,9 @@ func (m *Main) Run(args ...string) error {
 	case "query":
 		name := query.NewCommand()
 		name.Logger = m.Logger
-		if err := name.Run(args...); err != nil {
+		if err := name.Run(args...); err == query.ErrEmptyResult {
+			return err
+		} else if err != nil {
 			return fmt.Errorf("query: %s", err)
 		}
 	default:
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// defaultPort is used when -addr does not specify a port.
+const defaultPort = "8082"
+
+// canonicalizeAddr validates addr and returns it in host:port form. A URL
+// scheme such as http:// is removed and a missing port defaults to defaultPort.
+func canonicalizeAddr(addr string) (string, error) {
+	s := strings.TrimSpace(addr)
+	if s == "" {
+		return "", fmt.Errorf("invalid address %q: address is empty", addr)
+	}
+
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	s = strings.TrimSuffix(s, "/")
+	if strings.ContainsAny(s, "/?#@") {
+		return "", fmt.Errorf("invalid address %q: expected host:port", addr)
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		// no port; allow a bare or bracketed IPv6 address
+		host = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+		if strings.ContainsAny(host, ":[]") && net.ParseIP(host) == nil {
+			return "", fmt.Errorf("invalid address %q: expected host:port", addr)
+		}
+		port = ""
+	}
+
+	if port == "" {
+		port = defaultPort
+	}
+
+	if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+		return "", fmt.Errorf("invalid address %q: invalid port %q", addr, port)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
@@ -0,0 +1,46 @@
+package query
+
+import "testing"
+
+func TestCanonicalizeAddr(t *testing.T) {
+	cases := []struct {
+		n    string
+		addr string
+		exp  string
+		err  bool
+	}{
+		{n: "port only", addr: ":8082", exp: ":8082"},
+		{n: "host and port", addr: "localhost:9000", exp: "localhost:9000"},
+		{n: "host only", addr: "localhost", exp: "localhost:8082"},
+		{n: "empty port", addr: "localhost:", exp: "localhost:8082"},
+		{n: "http scheme", addr: "http://localhost:8082", exp: "localhost:8082"},
+		{n: "scheme and trailing slash", addr: "tcp://10.0.0.1/", exp: "10.0.0.1:8082"},
+		{n: "IPv6 with port", addr: "[::1]:9000", exp: "[::1]:9000"},
+		{n: "bracketed IPv6", addr: "[::1]", exp: "[::1]:8082"},
+		{n: "bare IPv6", addr: "::1", exp: "[::1]:8082"},
+		{n: "empty", addr: "", err: true},
+		{n: "non-numeric port", addr: "localhost:http", err: true},
+		{n: "port out of range", addr: "localhost:70000", err: true},
+		{n: "zero port", addr: "localhost:0", err: true},
+		{n: "path", addr: "http://localhost:8082/api", err: true},
+		{n: "too many colons", addr: "a:b:c", err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got, err := canonicalizeAddr(tc.addr)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.exp {
+				t.Errorf("unexpected address: got %q, exp %q", got, tc.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package query
+
+import (
+	"errors"
+	"io"
+)
+
+// errOutputLimit is returned by limitWriter once the output budget is spent.
+var errOutputLimit = errors.New("output limit reached")
+
+// limitWriter is an io.Writer which calls cancel once at least n bytes have
+// been written. Writes are not split, so the budget may be exceeded by the
+// final write; subsequent writes are discarded.
+type limitWriter struct {
+	w      io.Writer
+	n      int64
+	cancel func()
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if w.n <= 0 {
+		return 0, errOutputLimit
+	}
+
+	n, err := w.w.Write(p)
+	w.n -= int64(n)
+	if w.n <= 0 {
+		w.cancel()
+	}
+	return n, err
+}
@@ -0,0 +1,46 @@
+package query
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/pkg/file"
+)
+
+// createOutputFile creates a temporary file alongside path, which is moved to
+// path by commitOutputFile once the output is complete. The file is given the
+// same permissions as a file created by os.Create.
+func createOutputFile(path string) (*os.File, error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	// the temporary file is created with mode 0600
+	if err := f.Chmod(0666 &^ umask()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// commitOutputFile syncs and closes f and renames it to path, so readers of
+// path never observe a partially written file.
+func commitOutputFile(f *os.File, path string) error {
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := file.RenameFile(f.Name(), path); err != nil {
+		return err
+	}
+
+	return file.SyncDir(filepath.Dir(path))
+}
@@ -0,0 +1,15 @@
+// +build !windows
+
+package query
+
+import (
+	"os"
+	"syscall"
+)
+
+// umask returns the file mode creation mask of the process.
+func umask() os.FileMode {
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+	return os.FileMode(mask)
+}
@@ -0,0 +1,6 @@
+package query
+
+import "os"
+
+// umask returns zero, as Windows has no file mode creation mask.
+func umask() os.FileMode { return 0 }
@@ -0,0 +1,81 @@
+package query
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/logger"
+)
+
+// progress periodically reports the number of points received and the
+// average rate. Interactive terminals are updated in place, otherwise a
+// line is written for each report.
+type progress struct {
+	w        io.Writer
+	tty      bool
+	start    time.Time
+	n        uint64 // accessed atomically
+	reported bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newProgress(w io.Writer, interval time.Duration) *progress {
+	p := &progress{
+		w:     w,
+		tty:   logger.IsTerminal(w),
+		start: time.Now(),
+		done:  make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.run(interval)
+	return p
+}
+
+// Add increments the number of points received by n.
+func (p *progress) Add(n uint64) {
+	atomic.AddUint64(&p.n, n)
+}
+
+// Stop stops reporting and waits for the reporting goroutine to exit.
+func (p *progress) Stop() {
+	close(p.done)
+	p.wg.Wait()
+
+	if p.tty && p.reported {
+		fmt.Fprintln(p.w)
+	}
+}
+
+func (p *progress) run(interval time.Duration) {
+	defer p.wg.Done()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case now := <-t.C:
+			p.report(now)
+		}
+	}
+}
+
+func (p *progress) report(now time.Time) {
+	n := atomic.LoadUint64(&p.n)
+	rate := float64(n) / now.Sub(p.start).Seconds()
+
+	if p.tty {
+		fmt.Fprintf(p.w, "\rscanned %d points (%.0f/sec)", n, rate)
+	} else {
+		fmt.Fprintf(p.w, "scanned %d points (%.0f/sec)\n", n, rate)
+	}
+	p.reported = true
+}
@@ -7,7 +7,9 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -21,6 +23,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// DefaultProgressInterval is the default period between progress reports.
+const DefaultProgressInterval = 2 * time.Second
+
+// ErrEmptyResult is returned when -fail-if-empty is set and the query
+// returned no series.
+var ErrEmptyResult = errors.New("query returned no series")
+
 // Command represents the program execution for "store query".
 type Command struct {
 	// Standard input/output, overridden for testing.
@@ -28,6 +37,9 @@ type Command struct {
 	Stdout io.Writer
 	Logger *zap.Logger
 
+	// How to get environment variables. Normally set to os.Getenv, except for tests.
+	Getenv func(string) string
+
 	addr            string
 	cpuProfile      string
 	memProfile      string
@@ -36,30 +48,54 @@ type Command struct {
 	retentionPolicy string
 	startTime       int64
 	endTime         int64
+	endDefault      string
 	limit           uint64
 	slimit          uint64
 	soffset         uint64
 	desc            bool
 	silent          bool
+	failIfEmpty     bool
+	progress        bool
+	printCommand    bool
+	rate            int
+	maxOutputBytes  int64
+	timeout         time.Duration
+	verbose         bool
+	out             string
+	retries         int
+	retryBackoff    time.Duration
+	allowKeys       stringList
+	denyKeys        stringList
+	fieldKeys       stringList
+	tags            *tagFilter
 	expr            string
 	agg             string
 	grouping        string
 	keys            []string
+	groupExcept     bool
+	dumpRequest     string
+	replayRequest   string
 
 	aggType storage.Aggregate_AggregateType
 
+	progressInterval time.Duration
+
 	// response
 	integerSum  int64
 	unsignedSum uint64
 	floatSum    float64
 	pointCount  uint64
+	seriesCount uint64
+	wireBytes   uint64
 }
 
 // NewCommand returns a new instance of Command.
 func NewCommand() *Command {
 	return &Command{
-		Stderr: os.Stderr,
-		Stdout: os.Stdout,
+		Stderr:           os.Stderr,
+		Stdout:           os.Stdout,
+		Getenv:           os.Getenv,
+		progressInterval: DefaultProgressInterval,
 	}
 }
 
@@ -68,6 +104,11 @@ func parseTime(v string) (int64, error) {
 		return s.UnixNano(), nil
 	}
 
+	// date-only values are midnight UTC
+	if s, err := time.Parse("2006-01-02", v); err == nil {
+		return s.UnixNano(), nil
+	}
+
 	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
 		return i, nil
 	}
@@ -75,26 +116,92 @@ func parseTime(v string) (int64, error) {
 	return 0, errors.New("invalid time")
 }
 
+// defaultEndTime returns the end time to use when -end is not specified.
+// A mode of "max" selects models.MaxNanoTime, which includes any future-dated
+// shards, whereas "now" limits the query to the current time.
+func defaultEndTime(mode string, now time.Time) (int64, error) {
+	switch mode {
+	case "", "max":
+		return models.MaxNanoTime, nil
+	case "now":
+		return now.UnixNano(), nil
+	default:
+		return 0, fmt.Errorf("invalid end default: %s", mode)
+	}
+}
+
 // Run executes the command.
 func (cmd *Command) Run(args ...string) error {
+	fs, err := cmd.parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	if cmd.printCommand {
+		fmt.Fprintln(cmd.Stdout, cmd.commandLine(fs))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// cancel the query, or a pending retry, on interrupt
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return cmd.queryWithRetry(ctx, func() (storage.StorageClient, io.Closer, error) {
+		conn, err := yarpc.Dial(cmd.addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		return storage.NewStorageClient(conn), conn, nil
+	})
+}
+
+// parseFlags parses and validates the command line arguments.
+func (cmd *Command) parseFlags(args []string) (*flag.FlagSet, error) {
 	var start, end string
 	fs := flag.NewFlagSet("query", flag.ExitOnError)
 	fs.StringVar(&cmd.cpuProfile, "cpuprofile", "", "CPU profile name")
 	fs.StringVar(&cmd.memProfile, "memprofile", "", "memory profile name")
-	fs.StringVar(&cmd.addr, "addr", ":8082", "the RPC address")
+	fs.StringVar(&cmd.addr, "addr", ":8082", "the RPC address; defaults to $INFLUX_STORE_ADDR if set")
 	fs.StringVar(&cmd.orgID, "org-id", "", "Optional: org identifier when querying multi-tenant store")
-	fs.StringVar(&cmd.database, "database", "", "the database to query")
-	fs.StringVar(&cmd.retentionPolicy, "retention", "", "Optional: the retention policy to query")
-	fs.StringVar(&start, "start", "", "Optional: the start time to query (RFC3339 format)")
-	fs.StringVar(&end, "end", "", "Optional: the end time to query (RFC3339 format)")
+	fs.StringVar(&cmd.database, "database", "", "the database to query; defaults to $INFLUX_STORE_DATABASE if set")
+	fs.StringVar(&cmd.retentionPolicy, "retention", "", "Optional: the retention policy to query; defaults to $INFLUX_STORE_RP if set")
+	fs.StringVar(&start, "start", "", "Optional: the start time to query (RFC3339 or YYYY-MM-DD format)")
+	fs.StringVar(&end, "end", "", "Optional: the end time to query (RFC3339 or YYYY-MM-DD format)")
+	fs.StringVar(&cmd.endDefault, "end-default", "max", "Optional: the end time used when -end is not set (max, now)")
 	fs.Uint64Var(&cmd.slimit, "slimit", 0, "Optional: limit number of series")
 	fs.Uint64Var(&cmd.soffset, "soffset", 0, "Optional: start offset for series")
 	fs.Uint64Var(&cmd.limit, "limit", 0, "Optional: limit number of values per series")
 	fs.BoolVar(&cmd.desc, "desc", false, "Optional: return results in descending order")
 	fs.BoolVar(&cmd.silent, "silent", false, "silence output")
+	fs.StringVar(&cmd.out, "out", "", "Optional: write series and points to the specified file, without color, rather than stdout")
+	fs.BoolVar(&cmd.verbose, "verbose", false, "Optional: print additional statistics, such as the encoded size of the responses")
+	fs.BoolVar(&cmd.failIfEmpty, "fail-if-empty", false, "Optional: return an error if no series are returned")
+	fs.BoolVar(&cmd.progress, "progress", false, "Optional: periodically report the number of points received to stderr")
 	fs.StringVar(&cmd.expr, "expr", "", "InfluxQL conditional expression")
 	fs.StringVar(&cmd.agg, "agg", "", "aggregate functions (sum, count)")
 	fs.StringVar(&cmd.grouping, "grouping", "", "comma-separated list of tags to specify series order")
+	fs.BoolVar(&cmd.groupExcept, "group-except", false, "Optional: group series by all tags except those specified by -grouping")
+	fs.StringVar(&cmd.dumpRequest, "dump-request", "", "Optional: write the encoded request to the specified file")
+	fs.StringVar(&cmd.replayRequest, "replay", "", "Optional: send the request previously written by -dump-request to the specified file, ignoring other query flags")
+	fs.Var(&cmd.allowKeys, "allow-key", "Optional: only output the specified tag key; may be repeated")
+	fs.Var(&cmd.fieldKeys, "field", "Optional: treat the specified key in -expr as a field rather than a tag; may be repeated")
+	fs.Var(&cmd.denyKeys, "deny-key", "Optional: never output the specified tag key, which takes precedence over -allow-key; may be repeated")
+	fs.Int64Var(&cmd.maxOutputBytes, "max-output-bytes", 0, "Optional: cancel the query once the specified number of bytes have been written; zero is unlimited")
+	fs.IntVar(&cmd.retries, "retries", 0, "Optional: number of times to restart the query if the RPC fails before any response is received")
+	fs.DurationVar(&cmd.retryBackoff, "retry-backoff", time.Second, "Optional: delay before the first retry, which doubles after each attempt")
+	fs.DurationVar(&cmd.timeout, "timeout", 0, "Optional: cancel the query, including any retries, if it has not completed within the specified duration; zero is no timeout")
+	fs.IntVar(&cmd.rate, "rate", 0, "Optional: limit output to the specified number of lines per second; zero is unlimited")
+	fs.BoolVar(&cmd.printCommand, "print-command", false, "Optional: print a command which reproduces this query with absolute start and end times")
 
 	fs.SetOutput(cmd.Stdout)
 	fs.Usage = func() {
@@ -104,14 +211,18 @@ func (cmd *Command) Run(args ...string) error {
 	}
 
 	if err := fs.Parse(args); err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := cmd.applyEnvDefaults(fs); err != nil {
+		return nil, err
 	}
 
 	// set defaults
 	if start != "" {
 		t, err := parseTime(start)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		cmd.startTime = t
 
@@ -121,20 +232,23 @@ func (cmd *Command) Run(args ...string) error {
 	if end != "" {
 		t, err := parseTime(end)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		cmd.endTime = t
 
 	} else {
-		// set end time to max if it is not set.
-		cmd.endTime = models.MaxNanoTime
+		t, err := defaultEndTime(cmd.endDefault, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		cmd.endTime = t
 	}
 
 	if cmd.agg != "" {
 		tm := proto.EnumValueMap("storage.Aggregate_AggregateType")
 		agg, ok := tm[strings.ToUpper(cmd.agg)]
 		if !ok {
-			return errors.New("invalid aggregate function: " + cmd.agg)
+			return nil, errors.New("invalid aggregate function: " + cmd.agg)
 		}
 		cmd.aggType = storage.Aggregate_AggregateType(agg)
 	}
@@ -143,17 +257,87 @@ func (cmd *Command) Run(args ...string) error {
 		cmd.keys = strings.Split(cmd.grouping, ",")
 	}
 
-	if err := cmd.validate(); err != nil {
-		return err
+	addr, err := canonicalizeAddr(cmd.addr)
+	if err != nil {
+		return nil, err
 	}
+	cmd.addr = addr
 
-	conn, err := yarpc.Dial(cmd.addr)
-	if err != nil {
-		return err
+	cmd.tags = newTagFilter(cmd.allowKeys, cmd.denyKeys)
+
+	if cmd.replayRequest == "" {
+		if err := cmd.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return fs, nil
+}
+
+// envFlags lists the flags whose defaults may be set by environment variables.
+var envFlags = []struct {
+	flag, env string
+}{
+	{"addr", "INFLUX_STORE_ADDR"},
+	{"database", "INFLUX_STORE_DATABASE"},
+	{"retention", "INFLUX_STORE_RP"},
+}
+
+// applyEnvDefaults sets each flag in envFlags which was not specified on the
+// command line to the value of its environment variable, if not empty.
+func (cmd *Command) applyEnvDefaults(fs *flag.FlagSet) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	for _, ef := range envFlags {
+		if set[ef.flag] {
+			continue
+		}
+
+		if v := cmd.Getenv(ef.env); v != "" {
+			if err := fs.Set(ef.flag, v); err != nil {
+				return fmt.Errorf("invalid value %q for %s: %v", v, ef.env, err)
+			}
+		}
 	}
-	defer conn.Close()
+	return nil
+}
+
+// commandLine returns a shell command which reproduces the query described by
+// the flags set in fs. The start and end times are written as the absolute
+// timestamps they resolved to, so times relative to now are frozen.
+func (cmd *Command) commandLine(fs *flag.FlagSet) string {
+	args := []string{filepath.Base(os.Args[0]), "query"}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "start", "end", "end-default", "print-command":
+			return
+		}
+		if l, ok := f.Value.(*stringList); ok {
+			for _, v := range *l {
+				args = append(args, "-"+f.Name+"="+shellQuote(v))
+			}
+			return
+		}
+		args = append(args, "-"+f.Name+"="+shellQuote(f.Value.String()))
+	})
+
+	args = append(args,
+		"-start="+strconv.FormatInt(cmd.startTime, 10),
+		"-end="+strconv.FormatInt(cmd.endTime, 10),
+	)
 
-	return cmd.query(storage.NewStorageClient(conn))
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell if it contains special characters.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.,:/=@+", r))
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
 }
 
 func (cmd *Command) validate() error {
@@ -166,10 +350,48 @@ func (cmd *Command) validate() error {
 	if cmd.startTime != 0 && cmd.endTime != 0 && cmd.endTime < cmd.startTime {
 		return fmt.Errorf("end time before start time")
 	}
+	if cmd.rate < 0 {
+		return fmt.Errorf("rate must not be negative")
+	}
+	if cmd.retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	if cmd.maxOutputBytes < 0 {
+		return fmt.Errorf("max output bytes must not be negative")
+	}
 	return nil
 }
 
-func (cmd *Command) query(c storage.StorageClient) error {
+// readRequestFile decodes a request written by writeRequestFile.
+func readRequestFile(path string) (*storage.ReadRequest, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var req storage.ReadRequest
+	if err := req.Unmarshal(buf); err != nil {
+		return nil, fmt.Errorf("invalid request file %s: %v", path, err)
+	}
+	return &req, nil
+}
+
+// writeRequestFile writes the protobuf encoding of req to path.
+func writeRequestFile(path string, req *storage.ReadRequest) error {
+	buf, err := req.Marshal()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf, 0666)
+}
+
+// newRequest creates the ReadRequest described by the command flags, or
+// loads it from the -replay file if specified.
+func (cmd *Command) newRequest() (*storage.ReadRequest, error) {
+	if cmd.replayRequest != "" {
+		return readRequestFile(cmd.replayRequest)
+	}
+
 	var req storage.ReadRequest
 	req.Database = cmd.database
 	if cmd.orgID != "" {
@@ -186,6 +408,9 @@ func (cmd *Command) query(c storage.StorageClient) error {
 	req.PointsLimit = cmd.limit
 	req.Descending = cmd.desc
 	req.Grouping = cmd.keys
+	if cmd.groupExcept {
+		req.GroupMode = storage.GroupModeExcept
+	}
 
 	if cmd.aggType != storage.AggregateTypeNone {
 		req.Aggregate = &storage.Aggregate{Type: cmd.aggType}
@@ -194,25 +419,122 @@ func (cmd *Command) query(c storage.StorageClient) error {
 	if cmd.expr != "" {
 		expr, err := influxql.ParseExpr(cmd.expr)
 		if err != nil {
-			return nil
+			return nil, err
 		}
 		fmt.Fprintln(cmd.Stdout, expr)
-		var v exprToNodeVisitor
-		influxql.Walk(&v, expr)
-		if v.Err() != nil {
-			return v.Err()
+
+		// comparisons of time narrow the time range of the request, as the
+		// storage service would treat time as a tag key
+		cond, tr, err := influxql.ConditionExpr(expr, &influxql.NowValuer{Now: time.Now()})
+		if err != nil {
+			return nil, err
+		}
+		if err := narrowTimeRange(&req.TimestampRange, tr); err != nil {
+			return nil, err
 		}
 
-		req.Predicate = &storage.Predicate{Root: v.nodes[0]}
+		if cond != nil {
+			v := exprToNodeVisitor{fields: make(map[string]struct{}, len(cmd.fieldKeys))}
+			for _, k := range cmd.fieldKeys {
+				v.fields[k] = struct{}{}
+			}
+			influxql.Walk(&v, cond)
+			if v.Err() != nil {
+				return nil, v.Err()
+			}
+			if len(v.nodes) == 0 {
+				return nil, errMissingOperand
+			}
+
+			root := SimplifyPredicate(v.nodes[0])
+			if b, ok := booleanValue(root); !ok || !b {
+				// a predicate which is always true is equivalent to no predicate
+				req.Predicate = &storage.Predicate{Root: root}
+			}
+		}
 	}
 
-	stream, err := c.Read(context.Background(), &req)
+	return &req, nil
+}
+
+// narrowTimeRange intersects r with the bounds of tr which are set. It returns
+// an error if the resulting range is empty.
+func narrowTimeRange(r *storage.TimestampRange, tr influxql.TimeRange) error {
+	if !tr.Min.IsZero() && tr.Min.UnixNano() > r.Start {
+		r.Start = tr.Min.UnixNano()
+	}
+	if !tr.Max.IsZero() && tr.Max.UnixNano() < r.End {
+		r.End = tr.Max.UnixNano()
+	}
+
+	if r.Start > r.End {
+		return fmt.Errorf("end time %s before start time %s", formatTime(r.End), formatTime(r.Start))
+	}
+	return nil
+}
+
+func formatTime(t int64) string {
+	return time.Unix(0, t).UTC().Format(time.RFC3339Nano)
+}
+
+func (cmd *Command) query(ctx context.Context, c storage.StorageClient) error {
+	req, err := cmd.newRequest()
 	if err != nil {
-		fmt.Fprintln(cmd.Stdout, err)
 		return err
 	}
 
-	wr := bufio.NewWriter(os.Stdout)
+	if cmd.dumpRequest != "" {
+		if err := writeRequestFile(cmd.dumpRequest, req); err != nil {
+			return err
+		}
+	}
+
+	if isAlwaysFalse(req.Predicate) {
+		// the server would scan the entire time range without matching a series
+		fmt.Fprintln(cmd.Stderr, "predicate is always false; request not sent")
+		cmd.printSummary()
+		if cmd.failIfEmpty {
+			return ErrEmptyResult
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.Read(ctx, req)
+	if err != nil {
+		fmt.Fprintln(cmd.Stdout, err)
+		return retryable(err)
+	}
+
+	var w = cmd.Stdout
+	var f *os.File
+	if cmd.out != "" {
+		if f, err = createOutputFile(cmd.out); err != nil {
+			return err
+		}
+		defer func() {
+			// no-op once the file has been committed
+			f.Close()
+			os.Remove(f.Name())
+		}()
+		w = f
+	}
+	if cmd.maxOutputBytes > 0 {
+		// cancelling the request stops the server from reading further shards
+		w = &limitWriter{w: w, n: cmd.maxOutputBytes, cancel: cancel}
+	}
+	if cmd.rate > 0 {
+		w = newRateWriter(ctx, w, cmd.rate)
+	}
+	wr := bufio.NewWriter(w)
+
+	var p *progress
+	if cmd.progress {
+		p = newProgress(cmd.Stderr, cmd.progressInterval)
+		defer p.Stop()
+	}
 
 	now := time.Now()
 	defer func() {
@@ -228,25 +550,67 @@ func (cmd *Command) query(c storage.StorageClient) error {
 				break
 			}
 
+			if ctx.Err() == context.DeadlineExceeded {
+				// report what was received before the deadline
+				cmd.printSummary()
+				return ctx.Err()
+			}
+
+			if cmd.wireBytes == 0 {
+				return retryable(err)
+			}
+
 			return err
 		}
 
+		cmd.wireBytes += uint64(rep.Size())
+
+		n := cmd.pointCount
 		if cmd.silent {
 			cmd.processFramesSilent(rep.Frames)
 		} else {
-			cmd.processFrames(wr, rep.Frames)
+			cmd.processFrames(wr, rep.Frames, f == nil)
+		}
+
+		if p != nil {
+			p.Add(cmd.pointCount - n)
+		}
+
+		if ctx.Err() == context.Canceled {
+			fmt.Fprintln(cmd.Stderr, errOutputLimit)
+			break
 		}
 	}
 
-	fmt.Fprintln(cmd.Stdout)
-	fmt.Fprint(cmd.Stdout, "points(count): ", cmd.pointCount, ", sum(int64): ", cmd.integerSum, ", sum(uint64): ", cmd.unsignedSum, ", sum(float64): ", cmd.floatSum, "\n")
+	if f != nil {
+		if err := commitOutputFile(f, cmd.out); err != nil {
+			return err
+		}
+	}
+
+	cmd.printSummary()
+
+	if cmd.failIfEmpty && cmd.seriesCount == 0 {
+		return ErrEmptyResult
+	}
 
 	return nil
 }
 
+func (cmd *Command) printSummary() {
+	fmt.Fprintln(cmd.Stdout)
+	fmt.Fprint(cmd.Stdout, "points(count): ", cmd.pointCount, ", sum(int64): ", cmd.integerSum, ", sum(uint64): ", cmd.unsignedSum, ", sum(float64): ", cmd.floatSum, "\n")
+	if cmd.verbose {
+		fmt.Fprintln(cmd.Stdout, "wire_bytes:", cmd.wireBytes)
+	}
+}
+
 func (cmd *Command) processFramesSilent(frames []storage.ReadResponse_Frame) {
 	for _, frame := range frames {
 		switch f := frame.Data.(type) {
+		case *storage.ReadResponse_Frame_Series:
+			cmd.seriesCount++
+
 		case *storage.ReadResponse_Frame_IntegerPoints:
 			for _, v := range f.IntegerPoints.Values {
 				cmd.integerSum += v
@@ -274,7 +638,7 @@ func (cmd *Command) processFramesSilent(frames []storage.ReadResponse_Frame) {
 	}
 }
 
-func (cmd *Command) processFrames(wr *bufio.Writer, frames []storage.ReadResponse_Frame) {
+func (cmd *Command) processFrames(wr *bufio.Writer, frames []storage.ReadResponse_Frame, color bool) {
 	var buf [1024]byte
 	var line []byte
 
@@ -282,9 +646,14 @@ func (cmd *Command) processFrames(wr *bufio.Writer, frames []storage.ReadRespons
 		switch f := frame.Data.(type) {
 		case *storage.ReadResponse_Frame_Series:
 			s := f.Series
-			wr.WriteString("\033[36m")
+			if color {
+				wr.WriteString("\033[36m")
+			}
 			first := true
 			for _, t := range s.Tags {
+				if !cmd.tags.Allowed(t.Key) {
+					continue
+				}
 				if !first {
 					wr.WriteByte(',')
 				} else {
@@ -294,9 +663,14 @@ func (cmd *Command) processFrames(wr *bufio.Writer, frames []storage.ReadRespons
 				wr.WriteByte(':')
 				wr.Write(t.Value)
 			}
-			wr.WriteString("\033[0m\n")
+			if color {
+				wr.WriteString("\033[0m")
+			}
+			wr.WriteByte('\n')
 			wr.Flush()
 
+			cmd.seriesCount++
+
 		case *storage.ReadResponse_Frame_IntegerPoints:
 			p := f.IntegerPoints
 			for i := 0; i < len(p.Timestamps); i++ {
@@ -383,30 +757,84 @@ func (cmd *Command) processFrames(wr *bufio.Writer, frames []storage.ReadRespons
 type exprToNodeVisitor struct {
 	nodes []*storage.Node
 	err   error
+
+	// fields are the keys which refer to fields rather than tags, unless
+	// cast with ::tag.
+	fields map[string]struct{}
+}
+
+// isField returns true if ref refers to a field, either because it is cast to
+// a field type or its key is one of v.fields.
+func (v *exprToNodeVisitor) isField(ref *influxql.VarRef) bool {
+	switch ref.Type {
+	case influxql.Unknown:
+		_, ok := v.fields[ref.Val]
+		return ok
+	case influxql.Tag:
+		return false
+	default:
+		return true
+	}
+}
+
+// fieldComparison restricts cmp, which compares the values of field, to the
+// series of that field. The storage service compares the value of each
+// series with no regard to its field key, so cmp alone would apply to the
+// values of every field.
+func fieldComparison(field string, cmp *storage.Node) *storage.Node {
+	return &storage.Node{
+		NodeType: storage.NodeTypeParenExpression,
+		Children: []*storage.Node{{
+			NodeType: storage.NodeTypeLogicalExpression,
+			Value:    &storage.Node_Logical_{Logical: storage.LogicalAnd},
+			Children: []*storage.Node{
+				{
+					NodeType: storage.NodeTypeComparisonExpression,
+					Value:    &storage.Node_Comparison_{Comparison: storage.ComparisonEqual},
+					Children: []*storage.Node{
+						{NodeType: storage.NodeTypeTagRef, Value: &storage.Node_TagRefValue{TagRefValue: "_field"}},
+						{NodeType: storage.NodeTypeLiteral, Value: &storage.Node_StringValue{StringValue: field}},
+					},
+				},
+				cmp,
+			},
+		}},
+	}
 }
 
 func (v *exprToNodeVisitor) Err() error {
 	return v.err
 }
 
-func (v *exprToNodeVisitor) pop() (top *storage.Node) {
+// errMissingOperand is returned when an expression has fewer operands than its
+// operators require, which can only occur for expressions not produced by the
+// parser.
+var errMissingOperand = errors.New("malformed expression: missing operand")
+
+// pop removes the node on top of the stack. If the stack is empty, it sets
+// v.err and returns false.
+func (v *exprToNodeVisitor) pop() (top *storage.Node, ok bool) {
 	if len(v.nodes) < 1 {
-		panic("exprToNodeVisitor: stack empty")
+		v.err = errMissingOperand
+		return nil, false
 	}
 
 	top, v.nodes = v.nodes[len(v.nodes)-1], v.nodes[:len(v.nodes)-1]
-	return
+	return top, true
 }
 
-func (v *exprToNodeVisitor) pop2() (lhs, rhs *storage.Node) {
+// pop2 removes the two nodes on top of the stack. If the stack has fewer than
+// two nodes, it sets v.err and returns false.
+func (v *exprToNodeVisitor) pop2() (lhs, rhs *storage.Node, ok bool) {
 	if len(v.nodes) < 2 {
-		panic("exprToNodeVisitor: stack empty")
+		v.err = errMissingOperand
+		return nil, nil, false
 	}
 
 	rhs = v.nodes[len(v.nodes)-1]
 	lhs = v.nodes[len(v.nodes)-2]
 	v.nodes = v.nodes[:len(v.nodes)-2]
-	return
+	return lhs, rhs, true
 }
 
 func mapOpToComparison(op influxql.Token) storage.Node_Comparison {
@@ -423,6 +851,10 @@ func mapOpToComparison(op influxql.Token) storage.Node_Comparison {
 		return storage.ComparisonGreater
 	case influxql.GTE:
 		return storage.ComparisonGreaterEqual
+	case influxql.EQREGEX:
+		return storage.ComparisonRegex
+	case influxql.NEQREGEX:
+		return storage.ComparisonNotRegex
 
 	default:
 		return -1
@@ -447,12 +879,24 @@ func (v *exprToNodeVisitor) Visit(node influxql.Node) influxql.Visitor {
 		}
 
 		if comp := mapOpToComparison(n.Op); comp != -1 {
-			lhs, rhs := v.pop2()
-			v.nodes = append(v.nodes, &storage.Node{
+			lhs, rhs, ok := v.pop2()
+			if !ok {
+				return nil
+			}
+			if rhs.NodeType == storage.NodeTypeFieldRef {
+				v.err = fmt.Errorf("field %s must be on the left-hand side of a comparison", rhs.GetFieldRefValue())
+				return nil
+			}
+
+			node := &storage.Node{
 				NodeType: storage.NodeTypeComparisonExpression,
 				Value:    &storage.Node_Comparison_{Comparison: comp},
 				Children: []*storage.Node{lhs, rhs},
-			})
+			}
+			if lhs.NodeType == storage.NodeTypeFieldRef {
+				node = fieldComparison(lhs.GetFieldRefValue(), node)
+			}
+			v.nodes = append(v.nodes, node)
 		} else if n.Op == influxql.AND || n.Op == influxql.OR {
 			var op storage.Node_Logical
 			if n.Op == influxql.AND {
@@ -461,7 +905,10 @@ func (v *exprToNodeVisitor) Visit(node influxql.Node) influxql.Visitor {
 				op = storage.LogicalOr
 			}
 
-			lhs, rhs := v.pop2()
+			lhs, rhs, ok := v.pop2()
+			if !ok {
+				return nil
+			}
 			v.nodes = append(v.nodes, &storage.Node{
 				NodeType: storage.NodeTypeLogicalExpression,
 				Value:    &storage.Node_Logical_{Logical: op},
@@ -479,9 +926,13 @@ func (v *exprToNodeVisitor) Visit(node influxql.Node) influxql.Visitor {
 			return nil
 		}
 
+		expr, ok := v.pop()
+		if !ok {
+			return nil
+		}
 		v.nodes = append(v.nodes, &storage.Node{
 			NodeType: storage.NodeTypeParenExpression,
-			Children: []*storage.Node{v.pop()},
+			Children: []*storage.Node{expr},
 		})
 		return nil
 
@@ -513,7 +964,38 @@ func (v *exprToNodeVisitor) Visit(node influxql.Node) influxql.Visitor {
 		})
 		return nil
 
+	case *influxql.DurationLiteral:
+		// the predicate has no duration type, so durations are compared as
+		// integer nanoseconds
+		v.nodes = append(v.nodes, &storage.Node{
+			NodeType: storage.NodeTypeLiteral,
+			Value:    &storage.Node_IntegerValue{IntegerValue: int64(n.Val)},
+		})
+		return nil
+
+	case *influxql.BooleanLiteral:
+		v.nodes = append(v.nodes, &storage.Node{
+			NodeType: storage.NodeTypeLiteral,
+			Value:    &storage.Node_BooleanValue{BooleanValue: n.Val},
+		})
+		return nil
+
+	case *influxql.RegexLiteral:
+		v.nodes = append(v.nodes, &storage.Node{
+			NodeType: storage.NodeTypeLiteral,
+			Value:    &storage.Node_RegexValue{RegexValue: n.Val.String()},
+		})
+		return nil
+
 	case *influxql.VarRef:
+		if v.isField(n) {
+			v.nodes = append(v.nodes, &storage.Node{
+				NodeType: storage.NodeTypeFieldRef,
+				Value:    &storage.Node_FieldRefValue{FieldRefValue: n.Val},
+			})
+			return nil
+		}
+
 		v.nodes = append(v.nodes, &storage.Node{
 			NodeType: storage.NodeTypeTagRef,
 			Value:    &storage.Node_TagRefValue{TagRefValue: n.Val},
@@ -0,0 +1,1128 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/storage"
+	"github.com/influxdata/influxql"
+	"github.com/influxdata/yarpc"
+	"github.com/influxdata/yarpc/codes"
+	"github.com/influxdata/yarpc/status"
+)
+
+func TestParseTime(t *testing.T) {
+	cases := []struct {
+		n   string
+		v   string
+		exp int64
+		err bool
+	}{
+		{n: "RFC3339", v: "2023-01-01T12:30:00Z", exp: time.Date(2023, 1, 1, 12, 30, 0, 0, time.UTC).UnixNano()},
+		{n: "nanoseconds", v: "1000", exp: 1000},
+		{n: "date only", v: "2023-01-01", exp: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()},
+		{n: "date only leap day", v: "2024-02-29", exp: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC).UnixNano()},
+		{n: "invalid date", v: "2023-02-30", err: true},
+		{n: "invalid", v: "yesterday", err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got, err := parseTime(tc.v)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.exp {
+				t.Errorf("unexpected time: got %d, exp %d", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestDefaultEndTime(t *testing.T) {
+	now := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		n    string
+		mode string
+		exp  int64
+		err  bool
+	}{
+		{n: "unset defaults to max", mode: "", exp: models.MaxNanoTime},
+		{n: "max", mode: "max", exp: models.MaxNanoTime},
+		{n: "now", mode: "now", exp: now.UnixNano()},
+		{n: "invalid", mode: "later", err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got, err := defaultEndTime(tc.mode, now)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.exp {
+				t.Errorf("unexpected end time: got %d, exp %d", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestCommand_newRequest_EndDefault(t *testing.T) {
+	t.Run("max", func(t *testing.T) {
+		cmd := NewCommand()
+		if _, err := cmd.parseFlags([]string{"-database=db0"}); err != nil {
+			t.Fatal("parseFlags", err)
+		}
+
+		req, err := cmd.newRequest()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := req.TimestampRange.End; got != models.MaxNanoTime {
+			t.Errorf("unexpected end time: got %d, exp %d", got, models.MaxNanoTime)
+		}
+	})
+
+	t.Run("now", func(t *testing.T) {
+		before := time.Now().UnixNano()
+		cmd := NewCommand()
+		if _, err := cmd.parseFlags([]string{"-database=db0", "-end-default=now"}); err != nil {
+			t.Fatal("parseFlags", err)
+		}
+		after := time.Now().UnixNano()
+
+		req, err := cmd.newRequest()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := req.TimestampRange.End; got < before || got > after {
+			t.Errorf("unexpected end time: got %d, exp between %d and %d", got, before, after)
+		}
+	})
+}
+
+func TestCommand_FailIfEmpty(t *testing.T) {
+	series := storage.ReadResponse{
+		Frames: []storage.ReadResponse_Frame{
+			{Data: &storage.ReadResponse_Frame_Series{Series: &storage.ReadResponse_SeriesFrame{}}},
+		},
+	}
+
+	cases := []struct {
+		n         string
+		responses []storage.ReadResponse
+		exp       error
+	}{
+		{n: "empty stream", exp: ErrEmptyResult},
+		{n: "series", responses: []storage.ReadResponse{series}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.Stdout = &bytes.Buffer{}
+			cmd.database = "db0"
+			cmd.failIfEmpty = true
+
+			err := cmd.query(context.Background(), &storageClient{responses: tc.responses})
+			if err != tc.exp {
+				t.Errorf("unexpected error: got %v, exp %v", err, tc.exp)
+			}
+		})
+	}
+}
+
+func TestCommand_DumpReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store-query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "request")
+
+	cmd := NewCommand()
+	cmd.Stdout = &bytes.Buffer{}
+	cmd.database = "db0"
+	cmd.retentionPolicy = "rp0"
+	cmd.startTime = 10
+	cmd.endTime = 20
+	cmd.slimit = 5
+	cmd.desc = true
+	cmd.keys = []string{"host", "region"}
+	cmd.aggType = storage.AggregateTypeSum
+	cmd.expr = `host = 'a' AND region != 'west'`
+	cmd.dumpRequest = path
+
+	var dumped storageClient
+	if err := cmd.query(context.Background(), &dumped); err != nil {
+		t.Fatal("query", err)
+	}
+
+	replay := NewCommand()
+	replay.Stdout = &bytes.Buffer{}
+	replay.database = "ignored"
+	replay.replayRequest = path
+
+	var replayed storageClient
+	if err := replay.query(context.Background(), &replayed); err != nil {
+		t.Fatal("replay", err)
+	}
+
+	if !cmp.Equal(dumped.req, replayed.req) {
+		t.Errorf("unexpected replayed request; -dumped/+replayed\n%s", cmp.Diff(dumped.req, replayed.req))
+	}
+}
+
+func TestCommand_Progress(t *testing.T) {
+	points := storage.ReadResponse{
+		Frames: []storage.ReadResponse_Frame{
+			{Data: &storage.ReadResponse_Frame_IntegerPoints{IntegerPoints: &storage.ReadResponse_IntegerPointsFrame{
+				Timestamps: []int64{1, 2},
+				Values:     []int64{10, 20},
+			}}},
+		},
+	}
+	responses := []storage.ReadResponse{points, points, points}
+
+	cases := []struct {
+		n        string
+		delay    time.Duration
+		interval time.Duration
+		exp      bool
+	}{
+		{n: "slow stream", delay: 20 * time.Millisecond, interval: 5 * time.Millisecond, exp: true},
+		{n: "fast stream", interval: time.Minute, exp: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := NewCommand()
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			cmd.database = "db0"
+			cmd.silent = true
+			cmd.progress = true
+			cmd.progressInterval = tc.interval
+
+			if err := cmd.query(context.Background(), &storageClient{responses: responses, delay: tc.delay}); err != nil {
+				t.Fatal("query", err)
+			}
+
+			if got := strings.Contains(stderr.String(), "scanned "); got != tc.exp {
+				t.Errorf("unexpected progress output %q", stderr.String())
+			}
+			if strings.Contains(stdout.String(), "scanned ") {
+				t.Errorf("progress written to stdout %q", stdout.String())
+			}
+		})
+	}
+}
+
+func TestCommand_CommandLine(t *testing.T) {
+	cmd := NewCommand()
+	fs, err := cmd.parseFlags([]string{"-database=db0", "-expr=host = 'a b'", "-end-default=now", "-print-command"})
+	if err != nil {
+		t.Fatal("parseFlags", err)
+	}
+
+	got := cmd.commandLine(fs)
+	for _, exp := range []string{
+		" query ",
+		"-database=db0",
+		`-expr='host = '\''a b'\'''`,
+		"-start=" + strconv.FormatInt(cmd.startTime, 10),
+		"-end=" + strconv.FormatInt(cmd.endTime, 10),
+	} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("command %q does not contain %q", got, exp)
+		}
+	}
+
+	for _, name := range []string{"end-default", "print-command"} {
+		if strings.Contains(got, name) {
+			t.Errorf("command %q contains %q", got, name)
+		}
+	}
+}
+
+func TestCommand_EnvDefaults(t *testing.T) {
+	env := map[string]string{
+		"INFLUX_STORE_ADDR":     "store.example.com",
+		"INFLUX_STORE_DATABASE": "db1",
+		"INFLUX_STORE_RP":       "rp1",
+	}
+
+	cases := []struct {
+		n      string
+		args   []string
+		env    map[string]string
+		addr   string
+		db, rp string
+	}{
+		{n: "no environment", args: []string{"-database=db0"}, addr: ":8082", db: "db0"},
+		{n: "environment", env: env, addr: "store.example.com:8082", db: "db1", rp: "rp1"},
+		{
+			n:    "flags take precedence",
+			args: []string{"-addr=localhost:9000", "-database=db0", "-retention=autogen"},
+			env:  env,
+			addr: "localhost:9000", db: "db0", rp: "autogen",
+		},
+		{
+			n:    "empty flag takes precedence",
+			args: []string{"-retention="},
+			env:  env,
+			addr: "store.example.com:8082", db: "db1",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.Getenv = func(k string) string { return tc.env[k] }
+
+			if _, err := cmd.parseFlags(tc.args); err != nil {
+				t.Fatal("parseFlags", err)
+			}
+
+			if cmd.addr != tc.addr {
+				t.Errorf("unexpected addr: got %q, exp %q", cmd.addr, tc.addr)
+			}
+			if cmd.database != tc.db {
+				t.Errorf("unexpected database: got %q, exp %q", cmd.database, tc.db)
+			}
+			if cmd.retentionPolicy != tc.rp {
+				t.Errorf("unexpected retention policy: got %q, exp %q", cmd.retentionPolicy, tc.rp)
+			}
+		})
+	}
+}
+
+func TestCommand_MaxOutputBytes(t *testing.T) {
+	series := storage.ReadResponse{
+		Frames: []storage.ReadResponse_Frame{
+			{Data: &storage.ReadResponse_Frame_Series{Series: &storage.ReadResponse_SeriesFrame{
+				Tags: []storage.Tag{{Key: []byte("host"), Value: []byte("a")}},
+			}}},
+		},
+	}
+	responses := []storage.ReadResponse{series, series, series, series}
+
+	var stdout, stderr bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.database = "db0"
+	cmd.maxOutputBytes = 1
+
+	c := &storageClient{responses: responses}
+	if err := cmd.query(context.Background(), c); err != nil {
+		t.Fatal("query", err)
+	}
+
+	if c.stream.sent != 1 {
+		t.Errorf("unexpected responses received after output limit: got %d, exp 1", c.stream.sent)
+	}
+	if c.stream.ctx.Err() != context.Canceled {
+		t.Errorf("unexpected context error: got %v, exp %v", c.stream.ctx.Err(), context.Canceled)
+	}
+	if got := strings.Count(stdout.String(), "host:a"); got != 1 {
+		t.Errorf("unexpected series written: got %d, exp 1", got)
+	}
+	if !strings.Contains(stderr.String(), errOutputLimit.Error()) {
+		t.Errorf("expected output limit message, got %q", stderr.String())
+	}
+}
+
+func TestCommand_newRequest_Predicate(t *testing.T) {
+	tagEqual := func(key, value string) *storage.Node {
+		return &storage.Node{
+			NodeType: storage.NodeTypeComparisonExpression,
+			Value:    &storage.Node_Comparison_{Comparison: storage.ComparisonEqual},
+			Children: []*storage.Node{
+				{NodeType: storage.NodeTypeTagRef, Value: &storage.Node_TagRefValue{TagRefValue: key}},
+				{NodeType: storage.NodeTypeLiteral, Value: &storage.Node_StringValue{StringValue: value}},
+			},
+		}
+	}
+
+	cases := []struct {
+		n    string
+		expr string
+		exp  *storage.Node
+		err  bool
+	}{
+		{
+			n:    "and",
+			expr: `host = 'server01' AND region = 'west'`,
+			exp: &storage.Node{
+				NodeType: storage.NodeTypeLogicalExpression,
+				Value:    &storage.Node_Logical_{Logical: storage.LogicalAnd},
+				Children: []*storage.Node{tagEqual("host", "server01"), tagEqual("region", "west")},
+			},
+		},
+		{
+			n:    "boolean",
+			expr: `enabled = true AND host = 'a'`,
+			exp: &storage.Node{
+				NodeType: storage.NodeTypeLogicalExpression,
+				Value:    &storage.Node_Logical_{Logical: storage.LogicalAnd},
+				Children: []*storage.Node{
+					{
+						NodeType: storage.NodeTypeComparisonExpression,
+						Value:    &storage.Node_Comparison_{Comparison: storage.ComparisonEqual},
+						Children: []*storage.Node{
+							{NodeType: storage.NodeTypeTagRef, Value: &storage.Node_TagRefValue{TagRefValue: "enabled"}},
+							{NodeType: storage.NodeTypeLiteral, Value: &storage.Node_BooleanValue{BooleanValue: true}},
+						},
+					},
+					tagEqual("host", "a"),
+				},
+			},
+		},
+		{
+			n:    "regex",
+			expr: `host =~ /a.*/`,
+			exp: &storage.Node{
+				NodeType: storage.NodeTypeComparisonExpression,
+				Value:    &storage.Node_Comparison_{Comparison: storage.ComparisonRegex},
+				Children: []*storage.Node{
+					{NodeType: storage.NodeTypeTagRef, Value: &storage.Node_TagRefValue{TagRefValue: "host"}},
+					{NodeType: storage.NodeTypeLiteral, Value: &storage.Node_RegexValue{RegexValue: "a.*"}},
+				},
+			},
+		},
+		{
+			n:    "not regex",
+			expr: `host !~ /^web/`,
+			exp: &storage.Node{
+				NodeType: storage.NodeTypeComparisonExpression,
+				Value:    &storage.Node_Comparison_{Comparison: storage.ComparisonNotRegex},
+				Children: []*storage.Node{
+					{NodeType: storage.NodeTypeTagRef, Value: &storage.Node_TagRefValue{TagRefValue: "host"}},
+					{NodeType: storage.NodeTypeLiteral, Value: &storage.Node_RegexValue{RegexValue: "^web"}},
+				},
+			},
+		},
+		{
+			n:    "parse error",
+			expr: `host = `,
+			err:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.Stdout = ioutil.Discard
+			cmd.database = "db0"
+			cmd.expr = tc.expr
+
+			req, err := cmd.newRequest()
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if req.Predicate == nil {
+				t.Fatal("expected predicate")
+			}
+			if got := req.Predicate.Root; !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected predicate; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+
+			// the predicate must survive encoding the request
+			buf, err := req.Marshal()
+			if err != nil {
+				t.Fatal("Marshal", err)
+			}
+			var decoded storage.ReadRequest
+			if err := decoded.Unmarshal(buf); err != nil {
+				t.Fatal("Unmarshal", err)
+			}
+			if got := decoded.Predicate.Root; !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected decoded predicate; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestCommand_newRequest_Field(t *testing.T) {
+	compare := func(op storage.Node_Comparison, lhs, rhs *storage.Node) *storage.Node {
+		return &storage.Node{
+			NodeType: storage.NodeTypeComparisonExpression,
+			Value:    &storage.Node_Comparison_{Comparison: op},
+			Children: []*storage.Node{lhs, rhs},
+		}
+	}
+	tagRef := func(key string) *storage.Node {
+		return &storage.Node{NodeType: storage.NodeTypeTagRef, Value: &storage.Node_TagRefValue{TagRefValue: key}}
+	}
+	fieldRef := func(key string) *storage.Node {
+		return &storage.Node{NodeType: storage.NodeTypeFieldRef, Value: &storage.Node_FieldRefValue{FieldRefValue: key}}
+	}
+	str := func(v string) *storage.Node {
+		return &storage.Node{NodeType: storage.NodeTypeLiteral, Value: &storage.Node_StringValue{StringValue: v}}
+	}
+	integer := func(v int64) *storage.Node {
+		return &storage.Node{NodeType: storage.NodeTypeLiteral, Value: &storage.Node_IntegerValue{IntegerValue: v}}
+	}
+
+	tempGreater := &storage.Node{
+		NodeType: storage.NodeTypeParenExpression,
+		Children: []*storage.Node{{
+			NodeType: storage.NodeTypeLogicalExpression,
+			Value:    &storage.Node_Logical_{Logical: storage.LogicalAnd},
+			Children: []*storage.Node{
+				compare(storage.ComparisonEqual, tagRef("_field"), str("temp")),
+				compare(storage.ComparisonGreater, fieldRef("temp"), integer(50)),
+			},
+		}},
+	}
+
+	cases := []struct {
+		n      string
+		expr   string
+		fields []string
+		exp    *storage.Node
+		err    bool
+	}{
+		{n: "tag", expr: `temp = 'a'`, exp: compare(storage.ComparisonEqual, tagRef("temp"), str("a"))},
+		{n: "field", expr: `temp > 50`, fields: []string{"temp"}, exp: tempGreater},
+		{n: "field cast", expr: `temp::field > 50`, exp: tempGreater},
+		{n: "tag cast", expr: `temp::tag = 'a'`, fields: []string{"temp"}, exp: compare(storage.ComparisonEqual, tagRef("temp"), str("a"))},
+		{n: "field on right", expr: `50 < temp`, fields: []string{"temp"}, err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.Stdout = ioutil.Discard
+			cmd.database = "db0"
+			cmd.expr = tc.expr
+			cmd.fieldKeys = tc.fields
+
+			req, err := cmd.newRequest()
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := req.Predicate.GetRoot(); !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected predicate; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestExprToNodeVisitor_Duration(t *testing.T) {
+	expr, err := influxql.ParseExpr(`interval >= 1h`)
+	if err != nil {
+		t.Fatal("ParseExpr", err)
+	}
+
+	var v exprToNodeVisitor
+	influxql.Walk(&v, expr)
+	if v.Err() != nil {
+		t.Fatal("Walk", v.Err())
+	}
+
+	got := v.nodes[0].Children[1]
+	exp := &storage.Node{
+		NodeType: storage.NodeTypeLiteral,
+		Value:    &storage.Node_IntegerValue{IntegerValue: 3600000000000},
+	}
+	if !cmp.Equal(got, exp) {
+		t.Errorf("unexpected literal; -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+}
+
+func TestExprToNodeVisitor_MissingOperand(t *testing.T) {
+	hostEqual := &influxql.BinaryExpr{Op: influxql.EQ, LHS: &influxql.VarRef{Val: "host"}, RHS: &influxql.StringLiteral{Val: "a"}}
+
+	cases := []struct {
+		n    string
+		expr influxql.Expr
+	}{
+		{n: "comparison", expr: &influxql.BinaryExpr{Op: influxql.EQ, RHS: &influxql.StringLiteral{Val: "a"}}},
+		{n: "logical", expr: &influxql.BinaryExpr{Op: influxql.AND, LHS: hostEqual}},
+		{n: "paren", expr: &influxql.ParenExpr{}},
+		{n: "nested paren", expr: &influxql.BinaryExpr{Op: influxql.OR, LHS: hostEqual, RHS: &influxql.ParenExpr{}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("unexpected panic: %v", r)
+				}
+			}()
+
+			var v exprToNodeVisitor
+			influxql.Walk(&v, tc.expr)
+			if v.Err() != errMissingOperand {
+				t.Errorf("unexpected error; got %v, exp %v", v.Err(), errMissingOperand)
+			}
+		})
+	}
+}
+
+func TestExprToNodeVisitor_Precedence(t *testing.T) {
+	cases := []struct {
+		n    string
+		expr string
+		exp  string
+	}{
+		{n: "or and", expr: `a = 1 OR b = 2 AND c = 3`, exp: `OR(a, AND(b, c))`},
+		{n: "and or", expr: `a = 1 AND b = 2 OR c = 3`, exp: `OR(AND(a, b), c)`},
+		{n: "and or and", expr: `a = 1 AND b = 2 OR c = 3 AND d = 4`, exp: `OR(AND(a, b), AND(c, d))`},
+		{n: "or and or", expr: `a = 1 OR b = 2 AND c = 3 OR d = 4`, exp: `OR(OR(a, AND(b, c)), d)`},
+		{n: "or is left associative", expr: `a = 1 OR b = 2 OR c = 3`, exp: `OR(OR(a, b), c)`},
+		{n: "parens", expr: `(a = 1 OR b = 2) AND c = 3`, exp: `AND((OR(a, b)), c)`},
+		{n: "nested parens", expr: `a = 1 AND (b = 2 OR (c = 3 AND d = 4))`, exp: `AND(a, (OR(b, (AND(c, d)))))`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			expr, err := influxql.ParseExpr(tc.expr)
+			if err != nil {
+				t.Fatal("ParseExpr", err)
+			}
+
+			var v exprToNodeVisitor
+			influxql.Walk(&v, expr)
+			if v.Err() != nil {
+				t.Fatal("Walk", v.Err())
+			}
+
+			if got := logicalString(v.nodes[0]); got != tc.exp {
+				t.Errorf("unexpected tree: got %s, exp %s", got, tc.exp)
+			}
+		})
+	}
+}
+
+// logicalString formats the logical structure of n, such as OR(a, AND(b, c)),
+// writing each comparison as its tag key.
+func logicalString(n *storage.Node) string {
+	switch n.NodeType {
+	case storage.NodeTypeLogicalExpression:
+		op := "OR"
+		if n.GetLogical() == storage.LogicalAnd {
+			op = "AND"
+		}
+
+		args := make([]string, 0, len(n.Children))
+		for _, c := range n.Children {
+			args = append(args, logicalString(c))
+		}
+		return op + "(" + strings.Join(args, ", ") + ")"
+
+	case storage.NodeTypeParenExpression:
+		return "(" + logicalString(n.Children[0]) + ")"
+
+	case storage.NodeTypeComparisonExpression:
+		return n.Children[0].GetTagRefValue()
+
+	default:
+		return "?"
+	}
+}
+
+func TestCommand_newRequest_Time(t *testing.T) {
+	day1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	day2 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC).UnixNano()
+
+	cases := []struct {
+		n          string
+		expr       string
+		start, end int64 // flags
+		exp        storage.TimestampRange
+		pred       string
+		err        bool
+	}{
+		{
+			n:     "bounds",
+			expr:  `time >= '2020-01-01T00:00:00Z' AND time < '2020-01-02T00:00:00Z'`,
+			start: models.MinNanoTime, end: models.MaxNanoTime,
+			exp:  storage.TimestampRange{Start: day1, End: day2 - 1},
+			pred: "[none]",
+		},
+		{
+			n:     "time and tag",
+			expr:  `host = 'a' AND time > '2020-01-01T00:00:00Z'`,
+			start: models.MinNanoTime, end: models.MaxNanoTime,
+			exp:  storage.TimestampRange{Start: day1 + 1, End: models.MaxNanoTime},
+			pred: `'host' = "a"`,
+		},
+		{
+			n:     "flags are narrower",
+			expr:  `time >= '2020-01-01T00:00:00Z' AND time <= '2020-01-02T00:00:00Z'`,
+			start: day1 + 10, end: day2 - 10,
+			exp:  storage.TimestampRange{Start: day1 + 10, End: day2 - 10},
+			pred: "[none]",
+		},
+		{
+			n:     "contradictory bounds",
+			expr:  `time > '2020-01-02T00:00:00Z' AND time < '2020-01-01T00:00:00Z'`,
+			start: models.MinNanoTime, end: models.MaxNanoTime,
+			err: true,
+		},
+		{
+			n:     "outside flags",
+			expr:  `time < '2020-01-01T00:00:00Z'`,
+			start: day2, end: models.MaxNanoTime,
+			err: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.Stdout = ioutil.Discard
+			cmd.database = "db0"
+			cmd.startTime, cmd.endTime = tc.start, tc.end
+			cmd.expr = tc.expr
+
+			req, err := cmd.newRequest()
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !cmp.Equal(req.TimestampRange, tc.exp) {
+				t.Errorf("unexpected time range; -got/+exp\n%s", cmp.Diff(req.TimestampRange, tc.exp))
+			}
+			if got := storage.PredicateToExprString(req.Predicate); got != tc.pred {
+				t.Errorf("unexpected predicate: got %q, exp %q", got, tc.pred)
+			}
+		})
+	}
+}
+
+func TestCommand_newRequest_Grouping(t *testing.T) {
+	cases := []struct {
+		n    string
+		args []string
+		keys []string
+		mode storage.ReadRequest_Group
+	}{
+		{
+			n:    "group by",
+			args: []string{"-grouping=host,region"},
+			keys: []string{"host", "region"},
+			mode: storage.GroupModeBy,
+		},
+		{
+			n:    "group except",
+			args: []string{"-grouping=host", "-group-except"},
+			keys: []string{"host"},
+			mode: storage.GroupModeExcept,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			cmd := NewCommand()
+			if _, err := cmd.parseFlags(append([]string{"-database=db0"}, tc.args...)); err != nil {
+				t.Fatal("parseFlags", err)
+			}
+
+			req, err := cmd.newRequest()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !cmp.Equal(req.Grouping, tc.keys) {
+				t.Errorf("unexpected grouping; -got/+exp\n%s", cmp.Diff(req.Grouping, tc.keys))
+			}
+			if req.GroupMode != tc.mode {
+				t.Errorf("unexpected group mode: got %v, exp %v", req.GroupMode, tc.mode)
+			}
+		})
+	}
+}
+
+func TestCommand_Timeout(t *testing.T) {
+	points := storage.ReadResponse{
+		Frames: []storage.ReadResponse_Frame{
+			{Data: &storage.ReadResponse_Frame_IntegerPoints{IntegerPoints: &storage.ReadResponse_IntegerPointsFrame{
+				Timestamps: []int64{1, 2},
+				Values:     []int64{10, 20},
+			}}},
+		},
+	}
+
+	var stdout bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &stdout
+	cmd.database = "db0"
+	cmd.silent = true
+	cmd.timeout = 10 * time.Millisecond
+
+	c := &storageClient{responses: []storage.ReadResponse{points}, block: true}
+	if err := cmd.queryWithRetry(context.Background(), dialClients(c)); err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: got %v, exp %v", err, context.DeadlineExceeded)
+	}
+
+	if !strings.Contains(stdout.String(), "points(count): 2,") {
+		t.Errorf("expected partial summary, got %q", stdout.String())
+	}
+}
+
+func TestCommand_WireBytes(t *testing.T) {
+	responses := []storage.ReadResponse{
+		{Frames: []storage.ReadResponse_Frame{
+			{Data: &storage.ReadResponse_Frame_Series{Series: &storage.ReadResponse_SeriesFrame{
+				Tags: []storage.Tag{{Key: []byte("host"), Value: []byte("a")}},
+			}}},
+		}},
+		{Frames: []storage.ReadResponse_Frame{
+			{Data: &storage.ReadResponse_Frame_FloatPoints{FloatPoints: &storage.ReadResponse_FloatPointsFrame{
+				Timestamps: []int64{1, 2, 3},
+				Values:     []float64{1.5, 2.5, 3.5},
+			}}},
+		}},
+	}
+
+	var exp int
+	for i := range responses {
+		exp += responses[i].Size()
+	}
+
+	var stdout bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &stdout
+	cmd.database = "db0"
+	cmd.silent = true
+	cmd.verbose = true
+
+	if err := cmd.query(context.Background(), &storageClient{responses: responses}); err != nil {
+		t.Fatal("query", err)
+	}
+
+	if got := stdout.String(); !strings.Contains(got, "wire_bytes: "+strconv.Itoa(exp)+"\n") {
+		t.Errorf("expected wire_bytes: %d, got %q", exp, got)
+	}
+}
+
+func TestCommand_query_AlwaysFalse(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.database = "db0"
+	cmd.expr = `1 = 2 AND host = 'a'`
+
+	c := &storageClient{}
+	if err := cmd.query(context.Background(), c); err != nil {
+		t.Fatal("query", err)
+	}
+
+	if c.req != nil {
+		t.Errorf("unexpected request sent: %v", c.req)
+	}
+
+	if got := stdout.String(); !strings.Contains(got, "points(count): 0,") {
+		t.Errorf("expected zero-count summary, got %q", got)
+	}
+
+	cmd.failIfEmpty = true
+	if err := cmd.query(context.Background(), c); err != ErrEmptyResult {
+		t.Errorf("unexpected error: got %v, exp %v", err, ErrEmptyResult)
+	}
+}
+
+func TestCommand_Out(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store-query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	responses := []storage.ReadResponse{
+		{Frames: []storage.ReadResponse_Frame{
+			{Data: &storage.ReadResponse_Frame_Series{Series: &storage.ReadResponse_SeriesFrame{
+				Tags: []storage.Tag{{Key: []byte("host"), Value: []byte("a")}},
+			}}},
+			{Data: &storage.ReadResponse_Frame_IntegerPoints{IntegerPoints: &storage.ReadResponse_IntegerPointsFrame{
+				Timestamps: []int64{1},
+				Values:     []int64{10},
+			}}},
+		}},
+	}
+
+	var stdout bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &stdout
+	cmd.database = "db0"
+	cmd.out = filepath.Join(dir, "out.txt")
+
+	if err := cmd.query(context.Background(), &storageClient{responses: responses}); err != nil {
+		t.Fatal("query", err)
+	}
+
+	buf, err := ioutil.ReadFile(cmd.out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := string(buf), "host:a\n1 10\n"; got != exp {
+		t.Errorf("unexpected file contents: got %q, exp %q", got, exp)
+	}
+
+	if strings.Contains(stdout.String(), "host:a") {
+		t.Errorf("series written to stdout %q", stdout.String())
+	}
+
+	// the temporary file must have been renamed into place
+	if fis, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 1 {
+		t.Errorf("unexpected directory entries: got %d, exp 1", len(fis))
+	}
+
+	// the file must have the permissions of one created by os.Create
+	probe, err := os.Create(filepath.Join(dir, "probe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	probe.Close()
+
+	if fi, err := os.Stat(cmd.out); err != nil {
+		t.Fatal(err)
+	} else if pi, err := os.Stat(probe.Name()); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode() != pi.Mode() {
+		t.Errorf("unexpected mode: got %v, exp %v", fi.Mode(), pi.Mode())
+	}
+}
+
+func TestCommand_Retry(t *testing.T) {
+	series := storage.ReadResponse{
+		Frames: []storage.ReadResponse_Frame{
+			{Data: &storage.ReadResponse_Frame_Series{Series: &storage.ReadResponse_SeriesFrame{
+				Tags: []storage.Tag{{Key: []byte("host"), Value: []byte("a")}},
+			}}},
+		},
+	}
+
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset")}
+
+	cases := []struct {
+		n       string
+		retries int
+		clients []*storageClient
+		err     bool
+		exp     int
+	}{
+		{
+			n:       "fails once then succeeds",
+			retries: 2,
+			clients: []*storageClient{
+				{err: reset},
+				{responses: []storage.ReadResponse{series}},
+			},
+			exp: 2,
+		},
+		{
+			n:       "unavailable",
+			retries: 2,
+			clients: []*storageClient{
+				{err: status.Error(codes.Unavailable, "transport is closing")},
+				{responses: []storage.ReadResponse{series}},
+			},
+			exp: 2,
+		},
+		{
+			n:       "retries exhausted",
+			retries: 1,
+			clients: []*storageClient{
+				{err: reset},
+				{err: reset},
+			},
+			err: true,
+			exp: 2,
+		},
+		{
+			n: "no retries",
+			clients: []*storageClient{
+				{err: reset},
+			},
+			err: true,
+			exp: 1,
+		},
+		{
+			n:       "rejected by the server",
+			retries: 2,
+			clients: []*storageClient{
+				{err: errors.New("database not found")},
+			},
+			err: true,
+			exp: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := NewCommand()
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			cmd.database = "db0"
+			cmd.retries = tc.retries
+			cmd.retryBackoff = time.Millisecond
+
+			var dials int
+			err := cmd.queryWithRetry(context.Background(), func() (storage.StorageClient, io.Closer, error) {
+				c := tc.clients[dials]
+				dials++
+				return c, ioutil.NopCloser(nil), nil
+			})
+			if tc.err {
+				if _, ok := err.(retryableError); err == nil || ok {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if dials != tc.exp {
+				t.Errorf("unexpected dials: got %d, exp %d", dials, tc.exp)
+			}
+			if !tc.err && cmd.seriesCount != 1 {
+				t.Errorf("unexpected series count: got %d, exp 1", cmd.seriesCount)
+			}
+		})
+	}
+}
+
+func TestCommand_Retry_Timeout(t *testing.T) {
+	cmd := NewCommand()
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = ioutil.Discard
+	cmd.database = "db0"
+	cmd.retries = 1
+	cmd.retryBackoff = time.Hour
+	cmd.timeout = 10 * time.Millisecond
+
+	c := &storageClient{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	done := make(chan error, 1)
+	go func() { done <- cmd.queryWithRetry(context.Background(), dialClients(c)) }()
+
+	select {
+	case err := <-done:
+		if err != context.DeadlineExceeded {
+			t.Fatalf("unexpected error: got %v, exp %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout did not interrupt the retry backoff")
+	}
+}
+
+// dialClients returns a dialFunc which returns each of clients in turn.
+func dialClients(clients ...*storageClient) dialFunc {
+	return func() (storage.StorageClient, io.Closer, error) {
+		c := clients[0]
+		clients = clients[1:]
+		return c, ioutil.NopCloser(nil), nil
+	}
+}
+
+// storageClient is a storage.StorageClient which records the last request and
+// whose Read returns a stream of canned responses, each delayed by delay.
+type storageClient struct {
+	storage.StorageClient
+	req       *storage.ReadRequest
+	stream    *readClient
+	responses []storage.ReadResponse
+	delay     time.Duration
+	block     bool
+	err       error
+}
+
+func (c *storageClient) Read(ctx context.Context, req *storage.ReadRequest) (storage.Storage_ReadClient, error) {
+	c.req = req
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.stream = &readClient{ctx: ctx, responses: c.responses, delay: c.delay, block: c.block}
+	return c.stream, nil
+}
+
+// readClient returns the canned responses until they are exhausted or its
+// context is cancelled. If block is set, it waits for cancellation rather than
+// returning io.EOF once the responses are exhausted.
+type readClient struct {
+	yarpc.ClientStream
+	ctx       context.Context
+	responses []storage.ReadResponse
+	delay     time.Duration
+	block     bool
+	sent      int
+}
+
+func (c *readClient) Recv() (*storage.ReadResponse, error) {
+	var rep storage.ReadResponse
+	if err := c.RecvMsg(&rep); err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
+
+func (c *readClient) RecvMsg(m interface{}) error {
+	time.Sleep(c.delay)
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+	if len(c.responses) == 0 {
+		if c.block {
+			<-c.ctx.Done()
+			return c.ctx.Err()
+		}
+		return io.EOF
+	}
+
+	*m.(*storage.ReadResponse) = c.responses[0]
+	c.responses = c.responses[1:]
+	c.sent++
+	return nil
+}
@@ -0,0 +1,32 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/influxdata/influxdb/pkg/limiter"
+)
+
+// rateWriter is an io.Writer which limits the number of lines written to the
+// underlying writer per second.
+type rateWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter limiter.Rate
+}
+
+func newRateWriter(ctx context.Context, w io.Writer, linesPerSec int) *rateWriter {
+	return &rateWriter{ctx: ctx, w: w, limiter: limiter.NewRate(linesPerSec, 1)}
+}
+
+// Write waits for a token for each line in p before writing it. It returns
+// the error of ctx if ctx is done while waiting.
+func (w *rateWriter) Write(p []byte) (int, error) {
+	for i := bytes.Count(p, []byte{'\n'}); i > 0; i-- {
+		if err := w.limiter.WaitN(w.ctx, 1); err != nil {
+			return 0, err
+		}
+	}
+	return w.w.Write(p)
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateWriter(t *testing.T) {
+	const (
+		rate  = 50
+		lines = 5
+	)
+
+	var buf bytes.Buffer
+	w := newRateWriter(context.Background(), &buf, rate)
+
+	start := time.Now()
+	for i := 0; i < lines; i++ {
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatal("Write", err)
+		}
+	}
+	got := time.Since(start)
+
+	// allow some slack for timer granularity
+	if exp := lines * time.Second / rate * 8 / 10; got < exp {
+		t.Errorf("output not paced: wrote %d lines in %v, exp at least %v", lines, got, exp)
+	}
+
+	if exp := strings.Repeat("line\n", lines); buf.String() != exp {
+		t.Errorf("unexpected output: got %q, exp %q", buf.String(), exp)
+	}
+}
+
+func TestRateWriter_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the initial burst is spent, so the first line waits a full second
+	var buf bytes.Buffer
+	w := newRateWriter(ctx, &buf, 1)
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := w.Write([]byte("line\n")); err != context.Canceled {
+		t.Errorf("unexpected error: got %v, exp %v", err, context.Canceled)
+	}
+
+	if got := time.Since(start); got >= time.Second/2 {
+		t.Errorf("write not unblocked by cancel: took %v", got)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
@@ -0,0 +1,95 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/influxdata/influxdb/services/storage"
+	"github.com/influxdata/yarpc/codes"
+	"github.com/influxdata/yarpc/status"
+)
+
+// retryableError wraps an RPC error which occurred before any response was
+// received, so the query may be restarted without duplicating output.
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string { return e.err.Error() }
+
+// isTransportError reports whether err indicates that the storage service
+// could not be reached, rather than that it rejected the request.
+func isTransportError(err error) bool {
+	if err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code() == codes.Unavailable
+	}
+	return false
+}
+
+// retryable returns err wrapped in a retryableError if it is a transport
+// error, and err otherwise.
+func retryable(err error) error {
+	if isTransportError(err) {
+		return retryableError{err}
+	}
+	return err
+}
+
+// dialFunc connects to the storage service.
+type dialFunc func() (storage.StorageClient, io.Closer, error)
+
+// queryWithRetry dials and runs the query, restarting it up to cmd.retries
+// times with exponential backoff if the connection fails or the storage
+// service is unavailable before any response is received. The query and any
+// pending retry are abandoned once ctx is done or -timeout has elapsed.
+func (cmd *Command) queryWithRetry(ctx context.Context, dial dialFunc) error {
+	if cmd.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cmd.timeout)
+		defer cancel()
+	}
+
+	backoff := cmd.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := cmd.dialAndQuery(ctx, dial)
+		if err == nil || attempt >= cmd.retries {
+			if e, ok := err.(retryableError); ok {
+				return e.err
+			}
+			return err
+		}
+
+		if _, ok := err.(retryableError); !ok {
+			return err
+		}
+
+		fmt.Fprintf(cmd.Stderr, "retrying in %v: %v\n", backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (cmd *Command) dialAndQuery(ctx context.Context, dial dialFunc) error {
+	c, closer, err := dial()
+	if err != nil {
+		return retryableError{err}
+	}
+	defer closer.Close()
+
+	return cmd.query(ctx, c)
+}
@@ -0,0 +1,245 @@
+package query
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/influxdb/services/storage"
+)
+
+// SimplifyPredicate folds comparisons between two literals into boolean
+// literals and removes branches of logical expressions which are always true
+// or always false. The returned node may be a boolean literal if the entire
+// predicate can be resolved without consulting the server.
+func SimplifyPredicate(n *storage.Node) *storage.Node {
+	if n == nil {
+		return nil
+	}
+
+	switch n.NodeType {
+	case storage.NodeTypeParenExpression:
+		if len(n.Children) != 1 {
+			return n
+		}
+
+		child := SimplifyPredicate(n.Children[0])
+		if child.NodeType != storage.NodeTypeLogicalExpression {
+			// parentheses only affect the evaluation of logical expressions
+			return child
+		}
+
+		return &storage.Node{
+			NodeType: storage.NodeTypeParenExpression,
+			Children: []*storage.Node{child},
+		}
+
+	case storage.NodeTypeLogicalExpression:
+		return simplifyLogical(n)
+
+	case storage.NodeTypeComparisonExpression:
+		if len(n.Children) != 2 {
+			return n
+		}
+
+		if v, ok := foldComparison(n.GetComparison(), n.Children[0], n.Children[1]); ok {
+			return booleanNode(v)
+		}
+
+		return n
+
+	default:
+		return n
+	}
+}
+
+func simplifyLogical(n *storage.Node) *storage.Node {
+	// identity is the value which does not affect the result, such as true for AND;
+	// its negation short-circuits the entire expression.
+	identity := n.GetLogical() == storage.LogicalAnd
+
+	children := make([]*storage.Node, 0, len(n.Children))
+	for _, c := range n.Children {
+		c = SimplifyPredicate(c)
+		if v, ok := booleanValue(c); ok {
+			if v != identity {
+				return booleanNode(v)
+			}
+			continue
+		}
+		children = append(children, c)
+	}
+
+	switch len(children) {
+	case 0:
+		return booleanNode(identity)
+	case 1:
+		return children[0]
+	default:
+		return &storage.Node{
+			NodeType: storage.NodeTypeLogicalExpression,
+			Value:    n.Value,
+			Children: children,
+		}
+	}
+}
+
+func booleanNode(v bool) *storage.Node {
+	return &storage.Node{
+		NodeType: storage.NodeTypeLiteral,
+		Value:    &storage.Node_BooleanValue{BooleanValue: v},
+	}
+}
+
+func booleanValue(n *storage.Node) (bool, bool) {
+	if n.NodeType != storage.NodeTypeLiteral {
+		return false, false
+	}
+
+	v, ok := n.Value.(*storage.Node_BooleanValue)
+	if !ok {
+		return false, false
+	}
+
+	return v.BooleanValue, true
+}
+
+// isAlwaysFalse reports whether the root of p is the boolean literal false,
+// which SimplifyPredicate returns when no series can match.
+func isAlwaysFalse(p *storage.Predicate) bool {
+	if p == nil || p.Root == nil {
+		return false
+	}
+
+	b, ok := booleanValue(p.Root)
+	return ok && !b
+}
+
+// foldComparison evaluates a comparison between two literal nodes. The second
+// return value is false if either node is not a literal or the operand types
+// cannot be compared.
+func foldComparison(op storage.Node_Comparison, lhs, rhs *storage.Node) (bool, bool) {
+	if lhs.NodeType != storage.NodeTypeLiteral || rhs.NodeType != storage.NodeTypeLiteral {
+		return false, false
+	}
+
+	switch l := lhs.Value.(type) {
+	case *storage.Node_StringValue:
+		switch r := rhs.Value.(type) {
+		case *storage.Node_StringValue:
+			return compare(op, strings.Compare(l.StringValue, r.StringValue))
+		case *storage.Node_RegexValue:
+			re, err := regexp.Compile(r.RegexValue)
+			if err != nil {
+				return false, false
+			}
+
+			switch op {
+			case storage.ComparisonRegex:
+				return re.MatchString(l.StringValue), true
+			case storage.ComparisonNotRegex:
+				return !re.MatchString(l.StringValue), true
+			}
+		}
+
+	case *storage.Node_BooleanValue:
+		if r, ok := rhs.Value.(*storage.Node_BooleanValue); ok {
+			switch op {
+			case storage.ComparisonEqual:
+				return l.BooleanValue == r.BooleanValue, true
+			case storage.ComparisonNotEqual:
+				return l.BooleanValue != r.BooleanValue, true
+			}
+		}
+
+	case *storage.Node_IntegerValue:
+		if r, ok := rhs.Value.(*storage.Node_IntegerValue); ok {
+			return compare(op, compareInt64(l.IntegerValue, r.IntegerValue))
+		}
+
+		if r, ok := numericValue(rhs); ok {
+			return compare(op, compareFloat64(float64(l.IntegerValue), r))
+		}
+
+	case *storage.Node_UnsignedValue:
+		if r, ok := rhs.Value.(*storage.Node_UnsignedValue); ok {
+			return compare(op, compareUint64(l.UnsignedValue, r.UnsignedValue))
+		}
+
+		if r, ok := numericValue(rhs); ok {
+			return compare(op, compareFloat64(float64(l.UnsignedValue), r))
+		}
+
+	case *storage.Node_FloatValue:
+		if r, ok := numericValue(rhs); ok {
+			return compare(op, compareFloat64(l.FloatValue, r))
+		}
+	}
+
+	return false, false
+}
+
+func numericValue(n *storage.Node) (float64, bool) {
+	switch v := n.Value.(type) {
+	case *storage.Node_IntegerValue:
+		return float64(v.IntegerValue), true
+	case *storage.Node_UnsignedValue:
+		return float64(v.UnsignedValue), true
+	case *storage.Node_FloatValue:
+		return v.FloatValue, true
+	default:
+		return 0, false
+	}
+}
+
+// compare maps the result of a three-way comparison to the outcome of op.
+func compare(op storage.Node_Comparison, cmp int) (bool, bool) {
+	switch op {
+	case storage.ComparisonEqual:
+		return cmp == 0, true
+	case storage.ComparisonNotEqual:
+		return cmp != 0, true
+	case storage.ComparisonLess:
+		return cmp < 0, true
+	case storage.ComparisonLessEqual:
+		return cmp <= 0, true
+	case storage.ComparisonGreater:
+		return cmp > 0, true
+	case storage.ComparisonGreaterEqual:
+		return cmp >= 0, true
+	default:
+		return false, false
+	}
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareUint64(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloat64(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
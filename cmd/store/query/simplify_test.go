@@ -0,0 +1,78 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/services/storage"
+	"github.com/influxdata/influxql"
+)
+
+func TestSimplifyPredicate(t *testing.T) {
+	cases := []struct {
+		n string
+		s string
+		e string
+	}{
+		{
+			n: "true AND tag",
+			s: `1 = 1 AND host = 'a'`,
+			e: `'host' = "a"`,
+		},
+		{
+			n: "tag AND true",
+			s: `host = 'a' AND 'x' = 'x'`,
+			e: `'host' = "a"`,
+		},
+		{
+			n: "false AND tag",
+			s: `1 = 2 AND host = 'a'`,
+			e: `false`,
+		},
+		{
+			n: "true OR tag",
+			s: `host = 'a' OR 2 > 1`,
+			e: `true`,
+		},
+		{
+			n: "false OR tag",
+			s: `1.5 >= 2 OR host = 'a'`,
+			e: `'host' = "a"`,
+		},
+		{
+			n: "nested parens",
+			s: `(1 = 1 AND (host = 'a' OR 1 != 1)) AND region = 'west'`,
+			e: `'host' = "a" AND 'region' = "west"`,
+		},
+		{
+			n: "parens folded to literal",
+			s: `(1 = 1) AND host = 'a'`,
+			e: `'host' = "a"`,
+		},
+		{
+			n: "no constants",
+			s: `host = 'a' OR region = 'west'`,
+			e: `'host' = "a" OR 'region' = "west"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			expr, err := influxql.ParseExpr(tc.s)
+			if err != nil {
+				t.Fatal("ParseExpr", err)
+			}
+
+			var v exprToNodeVisitor
+			influxql.Walk(&v, expr)
+			if v.Err() != nil {
+				t.Fatal("exprToNodeVisitor", v.Err())
+			}
+
+			got := storage.PredicateToExprString(&storage.Predicate{Root: SimplifyPredicate(v.nodes[0])})
+			if !cmp.Equal(got, tc.e) {
+				t.Errorf("unexpected predicate; -got/+exp\n%s", cmp.Diff(got, tc.e))
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package query
+
+import "strings"
+
+// stringList is a flag.Value which collects the values of a repeated flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// tagFilter determines which tag keys are written to the output. Denied keys
+// are always removed; if any keys are allowed, only those keys are written.
+type tagFilter struct {
+	allow map[string]struct{}
+	deny  map[string]struct{}
+}
+
+// newTagFilter returns a tagFilter for the specified keys or nil if both lists
+// are empty.
+func newTagFilter(allow, deny []string) *tagFilter {
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil
+	}
+
+	f := &tagFilter{deny: make(map[string]struct{}, len(deny))}
+	for _, k := range deny {
+		f.deny[k] = struct{}{}
+	}
+
+	if len(allow) > 0 {
+		f.allow = make(map[string]struct{}, len(allow))
+		for _, k := range allow {
+			f.allow[k] = struct{}{}
+		}
+	}
+
+	return f
+}
+
+// Allowed returns true if key should be written. A nil filter allows all keys.
+func (f *tagFilter) Allowed(key []byte) bool {
+	if f == nil {
+		return true
+	}
+
+	if _, ok := f.deny[string(key)]; ok {
+		return false
+	}
+
+	if f.allow != nil {
+		_, ok := f.allow[string(key)]
+		return ok
+	}
+
+	return true
+}
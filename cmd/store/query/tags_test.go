@@ -0,0 +1,41 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTagFilter(t *testing.T) {
+	keys := []string{"_m", "host", "region", "user"}
+
+	cases := []struct {
+		n     string
+		allow []string
+		deny  []string
+		exp   []string
+	}{
+		{n: "none", exp: keys},
+		{n: "allow only", allow: []string{"_m", "host"}, exp: []string{"_m", "host"}},
+		{n: "deny only", deny: []string{"user"}, exp: []string{"_m", "host", "region"}},
+		{n: "deny takes precedence", allow: []string{"host", "user"}, deny: []string{"user"}, exp: []string{"host"}},
+		{n: "unknown keys", allow: []string{"rack"}, deny: []string{"dc"}, exp: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			f := newTagFilter(tc.allow, tc.deny)
+
+			var got []string
+			for _, k := range keys {
+				if f.Allowed([]byte(k)) {
+					got = append(got, k)
+				}
+			}
+
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected keys; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
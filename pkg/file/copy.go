@@ -0,0 +1,86 @@
+package file
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// CopyError records the step and path of a failed CopyFile.
+type CopyError struct {
+	Op   string // open, read, write, chmod, sync, close or rename
+	Path string
+	Err  error
+}
+
+func (e *CopyError) Error() string {
+	return "copy " + e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+// CopyFile durably copies src to dst, preserving the file mode of src. The
+// data is written to a uniquely named temporary file alongside dst, which is
+// synced and then renamed to dst, so readers of dst never observe a partial
+// copy.
+func CopyFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return &CopyError{Op: "open", Path: src, Err: err}
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return &CopyError{Op: "open", Path: src, Err: err}
+	}
+
+	out, err := ioutil.TempFile(filepath.Dir(dst), filepath.Base(dst)+".tmp")
+	if err != nil {
+		return &CopyError{Op: "open", Path: dst, Err: err}
+	}
+	tmp := out.Name()
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, rerr := in.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				return &CopyError{Op: "write", Path: tmp, Err: err}
+			}
+		}
+		if rerr == io.EOF {
+			break
+		} else if rerr != nil {
+			return &CopyError{Op: "read", Path: src, Err: rerr}
+		}
+	}
+
+	// the temporary file is created with mode 0600
+	if err := out.Chmod(fi.Mode().Perm()); err != nil {
+		return &CopyError{Op: "chmod", Path: tmp, Err: err}
+	}
+
+	if err := out.Sync(); err != nil {
+		return &CopyError{Op: "sync", Path: tmp, Err: err}
+	}
+
+	if err := out.Close(); err != nil {
+		return &CopyError{Op: "close", Path: tmp, Err: err}
+	}
+
+	if err := RenameFile(tmp, dst); err != nil {
+		return &CopyError{Op: "rename", Path: dst, Err: err}
+	}
+
+	if err := SyncDir(filepath.Dir(dst)); err != nil {
+		return &CopyError{Op: "sync", Path: filepath.Dir(dst), Err: err}
+	}
+
+	return nil
+}
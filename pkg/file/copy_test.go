@@ -0,0 +1,102 @@
+package file_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/file"
+)
+
+func TestCopyFile(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	src, dst := filepath.Join(dir, "src"), filepath.Join(dir, "dst")
+	MustWriteFile(src, "data")
+	if err := os.Chmod(src, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := file.CopyFile(src, dst); err != nil {
+		t.Fatalf("CopyFile: %v", err)
+	}
+
+	if got := MustReadFile(dst); got != "data" {
+		t.Errorf("unexpected contents: got %q, exp %q", got, "data")
+	}
+
+	if runtime.GOOS != "windows" {
+		if fi, err := os.Stat(dst); err != nil {
+			t.Fatal(err)
+		} else if fi.Mode().Perm() != 0600 {
+			t.Errorf("unexpected mode: got %v, exp %v", fi.Mode().Perm(), os.FileMode(0600))
+		}
+	}
+
+	// the temporary file should have been renamed
+	if fis, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 2 {
+		t.Errorf("unexpected directory entries: got %d, exp 2", len(fis))
+	}
+}
+
+func TestCopyFile_ExistingTemp(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	// a file which happens to have the old fixed temporary name
+	src, dst := filepath.Join(dir, "src"), filepath.Join(dir, "dst")
+	MustWriteFile(src, "data")
+	MustWriteFile(dst+".tmp", "keep")
+
+	if err := file.CopyFile(src, dst); err != nil {
+		t.Fatalf("CopyFile: %v", err)
+	}
+
+	if got := MustReadFile(dst + ".tmp"); got != "keep" {
+		t.Errorf("unexpected contents: got %q, exp %q", got, "keep")
+	}
+}
+
+func TestCopyFile_Errors(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	t.Run("missing source", func(t *testing.T) {
+		err := file.CopyFile(filepath.Join(dir, "missing"), filepath.Join(dir, "dst"))
+		assertCopyError(t, err, "open")
+	})
+
+	t.Run("read-only destination", func(t *testing.T) {
+		if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+			t.Skip("directory permissions are not enforced")
+		}
+
+		src := filepath.Join(dir, "src")
+		MustWriteFile(src, "data")
+
+		ro := filepath.Join(dir, "ro")
+		if err := os.Mkdir(ro, 0555); err != nil {
+			t.Fatal(err)
+		}
+
+		err := file.CopyFile(src, filepath.Join(ro, "dst"))
+		assertCopyError(t, err, "open")
+	})
+}
+
+func assertCopyError(t *testing.T, err error, op string) {
+	t.Helper()
+
+	e, ok := err.(*file.CopyError)
+	if !ok {
+		t.Fatalf("unexpected error: got %v, exp *file.CopyError", err)
+	}
+	if e.Op != op {
+		t.Errorf("unexpected op: got %q, exp %q", e.Op, op)
+	}
+}
@@ -2,7 +2,26 @@
 
 package file
 
-import "os"
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// unsupportedSyncDevs records the devices whose file system has been found not
+// to support syncing directories, so later calls can skip their directories.
+// It is keyed by device rather than path so it stays small however many
+// directories are synced.
+var unsupportedSyncDevs struct {
+	mu   sync.RWMutex
+	devs map[uint64]struct{}
+	n    int32 // length of devs, read atomically to avoid locking when empty
+}
+
+// syncDirFile syncs an open directory. It is a variable so tests can simulate
+// file systems which do not support syncing directories.
+var syncDirFile = (*os.File).Sync
 
 func SyncDir(dirName string) error {
 	// fsync the dir to flush the rename
@@ -11,7 +30,73 @@ func SyncDir(dirName string) error {
 		return err
 	}
 	defer dir.Close()
-	return dir.Sync()
+
+	if atomic.LoadInt32(&unsupportedSyncDevs.n) > 0 && isUnsupportedSyncDir(dir) {
+		return nil
+	}
+
+	if err := syncDirFile(dir); err != nil {
+		if !isSyncDirUnsupported(err) {
+			return err
+		}
+		if dev, ok := deviceOf(dir); ok {
+			addUnsupportedSyncDev(dev)
+		}
+	}
+	return nil
+}
+
+// deviceOf returns the ID of the device containing f.
+func deviceOf(f *os.File) (uint64, bool) {
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, false
+	}
+
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Dev), true
+}
+
+func isUnsupportedSyncDir(dir *os.File) bool {
+	dev, ok := deviceOf(dir)
+	if !ok {
+		return false
+	}
+
+	unsupportedSyncDevs.mu.RLock()
+	_, ok = unsupportedSyncDevs.devs[dev]
+	unsupportedSyncDevs.mu.RUnlock()
+	return ok
+}
+
+func addUnsupportedSyncDev(dev uint64) {
+	unsupportedSyncDevs.mu.Lock()
+	defer unsupportedSyncDevs.mu.Unlock()
+
+	if unsupportedSyncDevs.devs == nil {
+		unsupportedSyncDevs.devs = make(map[uint64]struct{})
+	}
+	unsupportedSyncDevs.devs[dev] = struct{}{}
+	atomic.StoreInt32(&unsupportedSyncDevs.n, int32(len(unsupportedSyncDevs.devs)))
+}
+
+// isSyncDirUnsupported returns true if err indicates that the file system does
+// not support syncing directories, as is the case for some Samba, NFS and FUSE
+// mounts.
+func isSyncDirUnsupported(err error) bool {
+	if pe, ok := err.(*os.PathError); ok {
+		err = pe.Err
+	}
+
+	switch err {
+	case syscall.EINVAL, syscall.ENOTSUP, syscall.ENOSYS:
+		return true
+	default:
+		return false
+	}
 }
 
 // RenameFile will rename the source to target using os function.
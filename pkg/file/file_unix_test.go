@@ -0,0 +1,104 @@
+// +build !windows
+
+package file
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+)
+
+func TestIsSyncDirUnsupported(t *testing.T) {
+	cases := []struct {
+		n   string
+		err error
+		exp bool
+	}{
+		{n: "EINVAL", err: syscall.EINVAL, exp: true},
+		{n: "ENOTSUP", err: syscall.ENOTSUP, exp: true},
+		{n: "ENOSYS", err: syscall.ENOSYS, exp: true},
+		{n: "path error", err: &os.PathError{Op: "sync", Path: "/data", Err: syscall.ENOTSUP}, exp: true},
+		{n: "EIO", err: syscall.EIO, exp: false},
+		{n: "path error EIO", err: &os.PathError{Op: "sync", Path: "/data", Err: syscall.EIO}, exp: false},
+		{n: "other", err: errors.New("sync failed"), exp: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			if got := isSyncDirUnsupported(tc.err); got != tc.exp {
+				t.Errorf("unexpected result for %v: got %v, exp %v", tc.err, got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestSyncDir_Unsupported(t *testing.T) {
+	root, err := ioutil.TempDir("", "syncdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	a, b := filepath.Join(root, "a"), filepath.Join(root, "b")
+	for _, dir := range []string{a, b} {
+		if err := os.Mkdir(dir, 0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// simulate a file system which does not support syncing directories
+	var syncs int
+	var syncErr error
+	defer func(fn func(*os.File) error) { syncDirFile = fn }(syncDirFile)
+	syncDirFile = func(f *os.File) error {
+		syncs++
+		return syncErr
+	}
+
+	defer func() {
+		unsupportedSyncDevs.mu.Lock()
+		unsupportedSyncDevs.devs = nil
+		atomic.StoreInt32(&unsupportedSyncDevs.n, 0)
+		unsupportedSyncDevs.mu.Unlock()
+	}()
+
+	// other errors are returned and not recorded
+	syncErr = &os.PathError{Op: "sync", Path: a, Err: syscall.EIO}
+	if err := SyncDir(a); err != syncErr {
+		t.Fatalf("unexpected error: got %v, exp %v", err, syncErr)
+	}
+	if n := atomic.LoadInt32(&unsupportedSyncDevs.n); n != 0 {
+		t.Fatalf("unexpected number of devices: got %d, exp 0", n)
+	}
+
+	syncErr = &os.PathError{Op: "sync", Path: a, Err: syscall.ENOTSUP}
+	if err := SyncDir(a); err != nil {
+		t.Fatalf("unexpected error for unsupported directory: %v", err)
+	}
+	if n := atomic.LoadInt32(&unsupportedSyncDevs.n); n != 1 {
+		t.Fatalf("unexpected number of devices: got %d, exp 1", n)
+	}
+
+	// every directory on the device is now skipped without being synced
+	syncs = 0
+	for _, dir := range []string{a, b} {
+		if err := SyncDir(dir); err != nil {
+			t.Fatalf("unexpected error for unsupported directory: %v", err)
+		}
+	}
+	if syncs != 0 {
+		t.Errorf("unexpected syncs: got %d, exp 0", syncs)
+	}
+	if n := atomic.LoadInt32(&unsupportedSyncDevs.n); n != 1 {
+		t.Errorf("unexpected number of devices: got %d, exp 1", n)
+	}
+
+	// missing directories still fail to open
+	if err := SyncDir(filepath.Join(root, "missing")); err == nil {
+		t.Fatal("expected error")
+	}
+}
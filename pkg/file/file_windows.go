@@ -1,18 +1,45 @@
 package file
 
-import "os"
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
 
+var (
+	modkernel32     = syscall.NewLazyDLL("kernel32.dll")
+	procMoveFileExW = modkernel32.NewProc("MoveFileExW")
+)
+
+const (
+	movefileReplaceExisting = 0x1
+	movefileWriteThrough    = 0x8
+)
+
+// SyncDir is a no-op on Windows, which does not support syncing directories.
 func SyncDir(dirName string) error {
 	return nil
 }
 
-// RenameFile will rename the source to target using os function. If target exists it will be removed before renaming.
+// RenameFile will rename the source to target, replacing target if it exists.
 func RenameFile(oldpath, newpath string) error {
-	if _, err := os.Stat(newpath); err == nil {
-		if err = os.Remove(newpath); nil != err {
-			return err
-		}
+	from, err := syscall.UTF16PtrFromString(oldpath)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+
+	to, err := syscall.UTF16PtrFromString(newpath)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
 	}
 
-	return os.Rename(oldpath, newpath)
+	r, _, e := procMoveFileExW.Call(
+		uintptr(unsafe.Pointer(from)),
+		uintptr(unsafe.Pointer(to)),
+		movefileReplaceExisting|movefileWriteThrough,
+	)
+	if r == 0 {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: e}
+	}
+	return nil
 }
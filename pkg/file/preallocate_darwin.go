@@ -0,0 +1,50 @@
+package file
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	allocateContig = 0x2 // F_ALLOCATECONTIG
+	allocateAll    = 0x4 // F_ALLOCATEALL
+	peofPosMode    = 3   // F_PEOFPOSMODE
+)
+
+// fstore mirrors the fstore_t argument to fcntl(F_PREALLOCATE).
+type fstore struct {
+	flags      uint32
+	posmode    int32
+	offset     int64
+	length     int64
+	bytesalloc int64
+}
+
+// Preallocate reserves size bytes of disk space for f without changing its
+// size. A contiguous allocation is attempted first, falling back to any
+// available blocks.
+func Preallocate(f *os.File, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+
+	fst := fstore{flags: allocateContig | allocateAll, posmode: peofPosMode, length: size}
+	if err := fcntlPreallocate(f, &fst); err == nil {
+		return nil
+	}
+
+	fst.flags = allocateAll
+	if err := fcntlPreallocate(f, &fst); err != nil && err != syscall.ENOTSUP {
+		return &os.PathError{Op: "fcntl", Path: f.Name(), Err: err}
+	}
+	return nil
+}
+
+func fcntlPreallocate(f *os.File, fst *fstore) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_FCNTL, f.Fd(), uintptr(syscall.F_PREALLOCATE), uintptr(unsafe.Pointer(fst)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package file
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Preallocate reserves size bytes of disk space for f without changing its
+// size. Filesystems which do not support fallocate are ignored.
+func Preallocate(f *os.File, size int64) error {
+	if size <= 0 {
+		return nil
+	}
+
+	for {
+		// FALLOC_FL_KEEP_SIZE allocates blocks without changing the apparent
+		// size of the file
+		err := unix.Fallocate(int(f.Fd()), unix.FALLOC_FL_KEEP_SIZE, 0, size)
+		switch err {
+		case nil, unix.EOPNOTSUPP, unix.ENOSYS:
+			return nil
+		case unix.EINTR:
+			continue
+		default:
+			return &os.PathError{Op: "fallocate", Path: f.Name(), Err: err}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package file_test
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/file"
+	"golang.org/x/sys/unix"
+)
+
+func TestPreallocate(t *testing.T) {
+	f, err := ioutil.TempFile("", "preallocate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	const size = 1 << 20
+	if err := file.Preallocate(f, size); err != nil {
+		t.Fatalf("Preallocate: %v", err)
+	}
+
+	var st syscall.Stat_t
+	if err := syscall.Fstat(int(f.Fd()), &st); err != nil {
+		t.Fatal(err)
+	}
+
+	if st.Size != 0 {
+		t.Errorf("unexpected file size: got %d, exp 0", st.Size)
+	}
+
+	// Preallocate ignores filesystems without fallocate support, such as
+	// some tmpfs, overlay and network mounts, in which case nothing is
+	// allocated.
+	if err := unix.Fallocate(int(f.Fd()), unix.FALLOC_FL_KEEP_SIZE, 0, 1); err == unix.EOPNOTSUPP || err == unix.ENOSYS {
+		t.Skipf("fallocate not supported by %s", os.TempDir())
+	} else if st.Blocks == 0 {
+		t.Skipf("fallocate allocated no blocks on %s", os.TempDir())
+	}
+
+	// Blocks is always in 512-byte units
+	if allocated := st.Blocks * 512; allocated < size {
+		t.Errorf("unexpected allocated size: got %d, exp >= %d", allocated, size)
+	}
+}
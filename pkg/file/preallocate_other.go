@@ -0,0 +1,10 @@
+// +build !linux,!darwin
+
+package file
+
+import "os"
+
+// Preallocate is a no-op on platforms without a preallocation API.
+func Preallocate(f *os.File, size int64) error {
+	return nil
+}
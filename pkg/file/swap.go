@@ -0,0 +1,67 @@
+package file
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SwapDir replaces targetDir with newDir. The existing targetDir is renamed
+// aside, newDir is renamed into its place and the original is removed once the
+// parent directory has been synced. newDir cannot simply be renamed over
+// targetDir: on POSIX systems a rename only replaces an empty directory, and on
+// Windows it never replaces one. The two renames are not atomic, so another
+// process may briefly find that targetDir does not exist. If newDir cannot be
+// moved into place, the original targetDir is restored.
+func SwapDir(newDir, targetDir string) error {
+	parent := filepath.Dir(targetDir)
+
+	oldDir, err := renameAside(targetDir)
+	if os.IsNotExist(err) {
+		oldDir = ""
+	} else if err != nil {
+		return err
+	}
+
+	if err := os.Rename(newDir, targetDir); err != nil {
+		if oldDir != "" {
+			if rerr := os.Rename(oldDir, targetDir); rerr != nil {
+				return fmt.Errorf("swap %s: %v; restoring original from %s failed: %v", targetDir, err, oldDir, rerr)
+			}
+		}
+		return err
+	}
+
+	if err := SyncDir(parent); err != nil {
+		return err
+	}
+
+	if oldDir == "" {
+		return nil
+	}
+	return os.RemoveAll(oldDir)
+}
+
+// renameAside renames path to an unused name in the same directory, formed by
+// appending ".old" and a random suffix, and returns the new name. The name is
+// claimed by the rename itself rather than reserved beforehand. If a non-empty
+// directory already has the name, the rename fails and another suffix is tried.
+func renameAside(path string) (string, error) {
+	for i := 0; i < 100; i++ {
+		var b [8]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return "", err
+		}
+
+		name := path + ".old" + hex.EncodeToString(b[:])
+		if err := os.Rename(path, name); os.IsExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	return "", fmt.Errorf("rename %s: no unused name found", path)
+}
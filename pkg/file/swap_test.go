@@ -0,0 +1,88 @@
+package file_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/file"
+)
+
+func TestSwapDir(t *testing.T) {
+	root := MustTempDir()
+	defer os.RemoveAll(root)
+
+	target := filepath.Join(root, "shard")
+	newDir := filepath.Join(root, "shard.new")
+	MustWriteFile(filepath.Join(target, "data"), "old")
+	MustWriteFile(filepath.Join(newDir, "data"), "new")
+
+	if err := file.SwapDir(newDir, target); err != nil {
+		t.Fatalf("SwapDir: %v", err)
+	}
+
+	if got := MustReadFile(filepath.Join(target, "data")); got != "new" {
+		t.Errorf("unexpected target contents: got %q, exp %q", got, "new")
+	}
+
+	if _, err := os.Stat(newDir); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be moved, got %v", newDir, err)
+	}
+
+	// only the swapped directory should remain
+	if fis, err := ioutil.ReadDir(root); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 1 {
+		t.Errorf("unexpected directory entries: got %d, exp 1", len(fis))
+	}
+}
+
+func TestSwapDir_Rollback(t *testing.T) {
+	root := MustTempDir()
+	defer os.RemoveAll(root)
+
+	target := filepath.Join(root, "shard")
+	MustWriteFile(filepath.Join(target, "data"), "old")
+
+	// the new directory is missing, so moving it into place fails after the
+	// original has been renamed aside
+	if err := file.SwapDir(filepath.Join(root, "missing"), target); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if got := MustReadFile(filepath.Join(target, "data")); got != "old" {
+		t.Errorf("unexpected target contents: got %q, exp %q", got, "old")
+	}
+
+	if fis, err := ioutil.ReadDir(root); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 1 {
+		t.Errorf("unexpected directory entries: got %d, exp 1", len(fis))
+	}
+}
+
+func MustTempDir() string {
+	dir, err := ioutil.TempDir("", "swapdir")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+func MustWriteFile(path, data string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(data), 0666); err != nil {
+		panic(err)
+	}
+}
+
+func MustReadFile(path string) string {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return string(buf)
+}
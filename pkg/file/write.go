@@ -0,0 +1,47 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes data to a uniquely named temporary file alongside
+// path, syncs it and renames it to path, so readers of path never observe a
+// partial write. The file is given the permissions perm, which are not
+// subject to the umask. The temporary file is removed if any step fails.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+
+	if err = f.Chmod(perm); err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	if err = RenameFile(tmp, path); err != nil {
+		return err
+	}
+
+	return SyncDir(filepath.Dir(path))
+}
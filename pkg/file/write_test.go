@@ -0,0 +1,83 @@
+package file_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/file"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "data")
+	MustWriteFile(path, "old")
+
+	if err := file.WriteFileAtomic(path, []byte("new"), 0640); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+
+	if got := MustReadFile(path); got != "new" {
+		t.Errorf("unexpected contents: got %q, exp %q", got, "new")
+	}
+
+	if runtime.GOOS != "windows" {
+		if fi, err := os.Stat(path); err != nil {
+			t.Fatal(err)
+		} else if fi.Mode().Perm() != 0640 {
+			t.Errorf("unexpected mode: got %v, exp %v", fi.Mode().Perm(), os.FileMode(0640))
+		}
+	}
+
+	// the temporary file should have been renamed
+	if fis, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 1 {
+		t.Errorf("unexpected directory entries: got %d, exp 1", len(fis))
+	}
+}
+
+func TestWriteFileAtomic_ExistingTemp(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	// a file which happens to have the old fixed temporary name
+	path := filepath.Join(dir, "data")
+	MustWriteFile(path+".tmp", "keep")
+
+	if err := file.WriteFileAtomic(path, []byte("new"), 0666); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+
+	if got := MustReadFile(path + ".tmp"); got != "keep" {
+		t.Errorf("unexpected contents: got %q, exp %q", got, "keep")
+	}
+}
+
+func TestWriteFileAtomic_RenameError(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	// a non-empty directory cannot be replaced by a file
+	path := filepath.Join(dir, "data")
+	MustWriteFile(filepath.Join(path, "child"), "old")
+
+	if err := file.WriteFileAtomic(path, []byte("new"), 0666); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// the temporary file should have been removed
+	if fis, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 1 {
+		t.Errorf("unexpected directory entries: got %d, exp 1", len(fis))
+	}
+
+	if got := MustReadFile(filepath.Join(path, "child")); got != "old" {
+		t.Errorf("unexpected contents: got %q, exp %q", got, "old")
+	}
+}
@@ -77,7 +77,7 @@ type floatMultiShardBatchCursor struct {
 	itrs   tsdb.CursorIterators
 	req    *tsdb.CursorRequest
 	err    error
-	limit  uint64
+	limit  uint64 // maximum number of points; zero means no limit
 	count  uint64
 }
 
@@ -105,20 +105,24 @@ func (c *floatMultiShardBatchCursor) Err() error { return c.err }
 
 func (c *floatMultiShardBatchCursor) Next() (key []int64, value []float64) {
 	for {
+		if c.limit > 0 && c.count >= c.limit {
+			// the limit has been reached; avoid reading any further points
+			return nil, nil
+		}
+
 		ks, vs := c.FloatBatchCursor.Next()
 		if len(ks) == 0 {
 			if c.nextBatchCursor() {
 				continue
 			}
 		}
-		c.count += uint64(len(ks))
-		if c.count > c.limit {
-			diff := c.count - c.limit
-			c.count -= diff
-			rem := uint64(len(ks)) - diff
-			ks = ks[:rem]
-			vs = vs[:rem]
+
+		if c.limit > 0 {
+			if rem := c.limit - c.count; uint64(len(ks)) > rem {
+				ks, vs = ks[:rem], vs[:rem]
+			}
 		}
+		c.count += uint64(len(ks))
 		return ks, vs
 	}
 }
@@ -132,9 +136,15 @@ func (c *floatMultiShardBatchCursor) nextBatchCursor() bool {
 
 	var itr tsdb.CursorIterator
 	var cur tsdb.Cursor
+	var err error
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		if cur, err = itr.Next(c.ctx, c.req); err != nil {
+			c.FloatBatchCursor = FloatEmptyBatchCursor
+			c.itrs = nil
+			c.err = err
+			return false
+		}
 	}
 
 	var ok bool
@@ -281,7 +291,7 @@ type integerMultiShardBatchCursor struct {
 	itrs   tsdb.CursorIterators
 	req    *tsdb.CursorRequest
 	err    error
-	limit  uint64
+	limit  uint64 // maximum number of points; zero means no limit
 	count  uint64
 }
 
@@ -309,20 +319,24 @@ func (c *integerMultiShardBatchCursor) Err() error { return c.err }
 
 func (c *integerMultiShardBatchCursor) Next() (key []int64, value []int64) {
 	for {
+		if c.limit > 0 && c.count >= c.limit {
+			// the limit has been reached; avoid reading any further points
+			return nil, nil
+		}
+
 		ks, vs := c.IntegerBatchCursor.Next()
 		if len(ks) == 0 {
 			if c.nextBatchCursor() {
 				continue
 			}
 		}
-		c.count += uint64(len(ks))
-		if c.count > c.limit {
-			diff := c.count - c.limit
-			c.count -= diff
-			rem := uint64(len(ks)) - diff
-			ks = ks[:rem]
-			vs = vs[:rem]
+
+		if c.limit > 0 {
+			if rem := c.limit - c.count; uint64(len(ks)) > rem {
+				ks, vs = ks[:rem], vs[:rem]
+			}
 		}
+		c.count += uint64(len(ks))
 		return ks, vs
 	}
 }
@@ -336,9 +350,15 @@ func (c *integerMultiShardBatchCursor) nextBatchCursor() bool {
 
 	var itr tsdb.CursorIterator
 	var cur tsdb.Cursor
+	var err error
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		if cur, err = itr.Next(c.ctx, c.req); err != nil {
+			c.IntegerBatchCursor = IntegerEmptyBatchCursor
+			c.itrs = nil
+			c.err = err
+			return false
+		}
 	}
 
 	var ok bool
@@ -485,7 +505,7 @@ type unsignedMultiShardBatchCursor struct {
 	itrs   tsdb.CursorIterators
 	req    *tsdb.CursorRequest
 	err    error
-	limit  uint64
+	limit  uint64 // maximum number of points; zero means no limit
 	count  uint64
 }
 
@@ -513,20 +533,24 @@ func (c *unsignedMultiShardBatchCursor) Err() error { return c.err }
 
 func (c *unsignedMultiShardBatchCursor) Next() (key []int64, value []uint64) {
 	for {
+		if c.limit > 0 && c.count >= c.limit {
+			// the limit has been reached; avoid reading any further points
+			return nil, nil
+		}
+
 		ks, vs := c.UnsignedBatchCursor.Next()
 		if len(ks) == 0 {
 			if c.nextBatchCursor() {
 				continue
 			}
 		}
-		c.count += uint64(len(ks))
-		if c.count > c.limit {
-			diff := c.count - c.limit
-			c.count -= diff
-			rem := uint64(len(ks)) - diff
-			ks = ks[:rem]
-			vs = vs[:rem]
+
+		if c.limit > 0 {
+			if rem := c.limit - c.count; uint64(len(ks)) > rem {
+				ks, vs = ks[:rem], vs[:rem]
+			}
 		}
+		c.count += uint64(len(ks))
 		return ks, vs
 	}
 }
@@ -540,9 +564,15 @@ func (c *unsignedMultiShardBatchCursor) nextBatchCursor() bool {
 
 	var itr tsdb.CursorIterator
 	var cur tsdb.Cursor
+	var err error
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		if cur, err = itr.Next(c.ctx, c.req); err != nil {
+			c.UnsignedBatchCursor = UnsignedEmptyBatchCursor
+			c.itrs = nil
+			c.err = err
+			return false
+		}
 	}
 
 	var ok bool
@@ -689,7 +719,7 @@ type stringMultiShardBatchCursor struct {
 	itrs   tsdb.CursorIterators
 	req    *tsdb.CursorRequest
 	err    error
-	limit  uint64
+	limit  uint64 // maximum number of points; zero means no limit
 	count  uint64
 }
 
@@ -717,20 +747,24 @@ func (c *stringMultiShardBatchCursor) Err() error { return c.err }
 
 func (c *stringMultiShardBatchCursor) Next() (key []int64, value []string) {
 	for {
+		if c.limit > 0 && c.count >= c.limit {
+			// the limit has been reached; avoid reading any further points
+			return nil, nil
+		}
+
 		ks, vs := c.StringBatchCursor.Next()
 		if len(ks) == 0 {
 			if c.nextBatchCursor() {
 				continue
 			}
 		}
-		c.count += uint64(len(ks))
-		if c.count > c.limit {
-			diff := c.count - c.limit
-			c.count -= diff
-			rem := uint64(len(ks)) - diff
-			ks = ks[:rem]
-			vs = vs[:rem]
+
+		if c.limit > 0 {
+			if rem := c.limit - c.count; uint64(len(ks)) > rem {
+				ks, vs = ks[:rem], vs[:rem]
+			}
 		}
+		c.count += uint64(len(ks))
 		return ks, vs
 	}
 }
@@ -744,9 +778,15 @@ func (c *stringMultiShardBatchCursor) nextBatchCursor() bool {
 
 	var itr tsdb.CursorIterator
 	var cur tsdb.Cursor
+	var err error
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		if cur, err = itr.Next(c.ctx, c.req); err != nil {
+			c.StringBatchCursor = StringEmptyBatchCursor
+			c.itrs = nil
+			c.err = err
+			return false
+		}
 	}
 
 	var ok bool
@@ -865,7 +905,7 @@ type booleanMultiShardBatchCursor struct {
 	itrs   tsdb.CursorIterators
 	req    *tsdb.CursorRequest
 	err    error
-	limit  uint64
+	limit  uint64 // maximum number of points; zero means no limit
 	count  uint64
 }
 
@@ -893,20 +933,24 @@ func (c *booleanMultiShardBatchCursor) Err() error { return c.err }
 
 func (c *booleanMultiShardBatchCursor) Next() (key []int64, value []bool) {
 	for {
+		if c.limit > 0 && c.count >= c.limit {
+			// the limit has been reached; avoid reading any further points
+			return nil, nil
+		}
+
 		ks, vs := c.BooleanBatchCursor.Next()
 		if len(ks) == 0 {
 			if c.nextBatchCursor() {
 				continue
 			}
 		}
-		c.count += uint64(len(ks))
-		if c.count > c.limit {
-			diff := c.count - c.limit
-			c.count -= diff
-			rem := uint64(len(ks)) - diff
-			ks = ks[:rem]
-			vs = vs[:rem]
+
+		if c.limit > 0 {
+			if rem := c.limit - c.count; uint64(len(ks)) > rem {
+				ks, vs = ks[:rem], vs[:rem]
+			}
 		}
+		c.count += uint64(len(ks))
 		return ks, vs
 	}
 }
@@ -920,9 +964,15 @@ func (c *booleanMultiShardBatchCursor) nextBatchCursor() bool {
 
 	var itr tsdb.CursorIterator
 	var cur tsdb.Cursor
+	var err error
 	for cur == nil && len(c.itrs) > 0 {
 		itr, c.itrs = c.itrs[0], c.itrs[1:]
-		cur, _ = itr.Next(c.ctx, c.req)
+		if cur, err = itr.Next(c.ctx, c.req); err != nil {
+			c.BooleanBatchCursor = BooleanEmptyBatchCursor
+			c.itrs = nil
+			c.err = err
+			return false
+		}
 	}
 
 	var ok bool
@@ -62,7 +62,45 @@ func newCountBatchCursor(cur tsdb.Cursor) tsdb.Cursor {
 	}
 }
 
-func newMultiShardBatchCursor(ctx context.Context, row seriesRow, rr *readRequest) tsdb.Cursor {
+// createCursorIterators returns a cursor iterator for each shard which reports
+// errors as a ShardError. As with tsdb.CreateCursorIterators, shards which are
+// closed or disabled are skipped. Any other error is returned as a ShardError.
+func createCursorIterators(ctx context.Context, shards []*tsdb.Shard) (tsdb.CursorIterators, error) {
+	q := make(tsdb.CursorIterators, 0, len(shards))
+	for _, s := range shards {
+		cq, err := s.CreateCursorIterator(ctx)
+		if err == tsdb.ErrEngineClosed || err == tsdb.ErrShardDisabled {
+			continue
+		} else if err != nil {
+			return nil, &ShardError{ShardID: s.ID(), Err: err}
+		}
+
+		if cq != nil {
+			q = append(q, &shardCursorIterator{CursorIterator: cq, id: s.ID()})
+		}
+	}
+	if len(q) == 0 {
+		return nil, nil
+	}
+	return q, nil
+}
+
+// shardCursorIterator annotates errors from a shard's CursorIterator with the
+// shard ID.
+type shardCursorIterator struct {
+	tsdb.CursorIterator
+	id uint64
+}
+
+func (itr *shardCursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (tsdb.Cursor, error) {
+	cur, err := itr.CursorIterator.Next(ctx, r)
+	if err != nil {
+		return nil, &ShardError{ShardID: itr.id, Err: err}
+	}
+	return cur, nil
+}
+
+func newMultiShardBatchCursor(ctx context.Context, row seriesRow, rr *readRequest) (tsdb.Cursor, error) {
 	req := &tsdb.CursorRequest{
 		Name:      row.name,
 		Tags:      row.stags,
@@ -79,26 +117,29 @@ func newMultiShardBatchCursor(ctx context.Context, row seriesRow, rr *readReques
 
 	var shard tsdb.CursorIterator
 	var cur tsdb.Cursor
+	var err error
 	for cur == nil && len(row.query) > 0 {
 		shard, row.query = row.query[0], row.query[1:]
-		cur, _ = shard.Next(ctx, req)
+		if cur, err = shard.Next(ctx, req); err != nil {
+			return nil, err
+		}
 	}
 
 	if cur == nil {
-		return nil
+		return nil, nil
 	}
 
 	switch c := cur.(type) {
 	case tsdb.IntegerBatchCursor:
-		return newIntegerMultiShardBatchCursor(ctx, c, rr, req, row.query, cond)
+		return newIntegerMultiShardBatchCursor(ctx, c, rr, req, row.query, cond), nil
 	case tsdb.FloatBatchCursor:
-		return newFloatMultiShardBatchCursor(ctx, c, rr, req, row.query, cond)
+		return newFloatMultiShardBatchCursor(ctx, c, rr, req, row.query, cond), nil
 	case tsdb.UnsignedBatchCursor:
-		return newUnsignedMultiShardBatchCursor(ctx, c, rr, req, row.query, cond)
+		return newUnsignedMultiShardBatchCursor(ctx, c, rr, req, row.query, cond), nil
 	case tsdb.StringBatchCursor:
-		return newStringMultiShardBatchCursor(ctx, c, rr, req, row.query, cond)
+		return newStringMultiShardBatchCursor(ctx, c, rr, req, row.query, cond), nil
 	case tsdb.BooleanBatchCursor:
-		return newBooleanMultiShardBatchCursor(ctx, c, rr, req, row.query, cond)
+		return newBooleanMultiShardBatchCursor(ctx, c, rr, req, row.query, cond), nil
 	default:
 		panic(fmt.Sprintf("unreachable: %T", cur))
 	}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestMultiShardBatchCursor_ShardError(t *testing.T) {
+	errShard := errors.New("shard failed")
+
+	t.Run("open", func(t *testing.T) {
+		row := seriesRow{query: tsdb.CursorIterators{
+			&shardCursorIterator{CursorIterator: &cursorIterator{err: errShard}, id: 3},
+		}}
+
+		_, err := newMultiShardBatchCursor(context.Background(), row, &readRequest{limit: math.MaxUint64})
+		assertShardError(t, err, 3, errShard)
+	})
+
+	t.Run("next", func(t *testing.T) {
+		row := seriesRow{query: tsdb.CursorIterators{
+			&shardCursorIterator{CursorIterator: &cursorIterator{cur: &integerBatchCursor{ts: []int64{1, 2}, vs: []int64{10, 20}}}, id: 1},
+			&shardCursorIterator{CursorIterator: &cursorIterator{err: errShard}, id: 2},
+		}}
+
+		cur, err := newMultiShardBatchCursor(context.Background(), row, &readRequest{limit: math.MaxUint64})
+		if err != nil {
+			t.Fatal("newMultiShardBatchCursor", err)
+		}
+
+		ic := cur.(tsdb.IntegerBatchCursor)
+		for {
+			if ks, _ := ic.Next(); len(ks) == 0 {
+				break
+			}
+		}
+		assertShardError(t, cur.Err(), 2, errShard)
+	})
+}
+
+func TestCreateCursorIterators(t *testing.T) {
+	ts, closeStore := mustOpenTSDBStore(t, `cpu,host=a v=1 10`, `cpu,host=b v=2 20`, `cpu,host=c v=3 30`)
+	defer closeStore()
+
+	ts.Shard(1).SetEnabled(false)
+	if err := ts.Shard(2).Close(); err != nil {
+		t.Fatal("Close", err)
+	}
+
+	t.Run("skips disabled and closed shards", func(t *testing.T) {
+		q, err := createCursorIterators(context.Background(), ts.Shards([]uint64{1, 2, 3}))
+		if err != nil {
+			t.Fatal("createCursorIterators", err)
+		}
+		if len(q) != 1 {
+			t.Fatalf("unexpected number of iterators: got %d, exp 1", len(q))
+		}
+		if id := q[0].(*shardCursorIterator).id; id != 3 {
+			t.Errorf("unexpected shard: got %d, exp 3", id)
+		}
+	})
+
+	t.Run("no available shards", func(t *testing.T) {
+		q, err := createCursorIterators(context.Background(), ts.Shards([]uint64{1, 2}))
+		if err != nil {
+			t.Fatal("createCursorIterators", err)
+		}
+		if q != nil {
+			t.Errorf("unexpected iterators: %v", q)
+		}
+	})
+}
+
+func TestResultSet_PointsLimit(t *testing.T) {
+	// each series has 5 points spread across two shards
+	newRows := func() []seriesRow {
+		var rows []seriesRow
+		for _, host := range []string{"a", "b"} {
+			rows = append(rows, seriesRow{
+				name:  []byte("cpu"),
+				stags: models.NewTags(map[string]string{"host": host}),
+				field: "v",
+				query: tsdb.CursorIterators{
+					&cursorIterator{cur: &integerBatchCursor{ts: []int64{1, 2}, vs: []int64{10, 20}}},
+					&cursorIterator{cur: &integerBatchCursor{ts: []int64{3, 4, 5}, vs: []int64{30, 40, 50}}},
+				},
+			})
+		}
+		return rows
+	}
+
+	cases := []struct {
+		n     string
+		limit uint64
+		exp   []int
+	}{
+		{n: "zero is unlimited", limit: 0, exp: []int{5, 5}},
+		{n: "one", limit: 1, exp: []int{1, 1}},
+		{n: "first shard", limit: 2, exp: []int{2, 2}},
+		{n: "spans shards", limit: 3, exp: []int{3, 3}},
+		{n: "exact count", limit: 5, exp: []int{5, 5}},
+		{n: "count plus one", limit: 6, exp: []int{5, 5}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			rs := &ResultSet{
+				req: readRequest{ctx: context.Background(), limit: tc.limit},
+				cur: &sliceSeriesCursor{rows: newRows()},
+			}
+			defer rs.Close()
+
+			var got []int
+			for rs.Next() {
+				ic := rs.Cursor().(tsdb.IntegerBatchCursor)
+
+				var n int
+				for {
+					ks, vs := ic.Next()
+					if len(ks) == 0 {
+						break
+					}
+					if len(ks) != len(vs) {
+						t.Fatalf("unexpected values; got %d, exp %d", len(vs), len(ks))
+					}
+					n += len(ks)
+				}
+				ic.Close()
+				got = append(got, n)
+			}
+
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected point counts; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func assertShardError(t *testing.T, err error, id uint64, cause error) {
+	t.Helper()
+
+	se, ok := err.(*ShardError)
+	if !ok {
+		t.Fatalf("unexpected error: got %T(%v), exp *ShardError", err, err)
+	}
+	if se.ShardID != id {
+		t.Errorf("unexpected shard ID: got %d, exp %d", se.ShardID, id)
+	}
+	if se.Err != cause {
+		t.Errorf("unexpected cause: got %v, exp %v", se.Err, cause)
+	}
+}
+
+// cursorIterator is a tsdb.CursorIterator which returns a single cursor or error.
+type cursorIterator struct {
+	cur tsdb.Cursor
+	err error
+}
+
+func (itr *cursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (tsdb.Cursor, error) {
+	return itr.cur, itr.err
+}
+
+// integerBatchCursor is a tsdb.IntegerBatchCursor which returns its points in a
+// single batch.
+type integerBatchCursor struct {
+	ts []int64
+	vs []int64
+}
+
+func (c *integerBatchCursor) Close()     {}
+func (c *integerBatchCursor) Err() error { return nil }
+
+func (c *integerBatchCursor) Next() (keys []int64, values []int64) {
+	keys, values = c.ts, c.vs
+	c.ts, c.vs = nil, nil
+	return keys, values
+}
@@ -14,6 +14,22 @@ type Config struct {
 	Enabled     bool   `toml:"enabled"`
 	LogEnabled  bool   `toml:"log-enabled"` // verbose logging
 	BindAddress string `toml:"bind-address"`
+
+	// MaxConcurrentReads limits the number of reads accessing shards at once.
+	// Zero means no limit.
+	MaxConcurrentReads int `toml:"max-concurrent-reads"`
+
+	// RejectExcessReads causes reads to fail rather than wait when
+	// MaxConcurrentReads reads are already accessing shards.
+	RejectExcessReads bool `toml:"reject-excess-reads"`
+
+	// RejectUnknownGroupKeys causes grouped reads to fail if a group key is
+	// not a tag key of any series matching the predicate.
+	RejectUnknownGroupKeys bool `toml:"reject-unknown-group-keys"`
+
+	// RequireFullCoverage causes reads with both a start and an end time to
+	// fail if the shard groups do not cover the entire time range.
+	RequireFullCoverage bool `toml:"require-full-coverage"`
 }
 
 // NewConfig returns a new Config with default settings.
@@ -34,8 +50,12 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 	}
 
 	return diagnostics.RowFromMap(map[string]interface{}{
-		"enabled":      true,
-		"log-enabled":  c.LogEnabled,
-		"bind-address": c.BindAddress,
+		"enabled":                   true,
+		"log-enabled":               c.LogEnabled,
+		"bind-address":              c.BindAddress,
+		"max-concurrent-reads":      c.MaxConcurrentReads,
+		"reject-excess-reads":       c.RejectExcessReads,
+		"reject-unknown-group-keys": c.RejectUnknownGroupKeys,
+		"require-full-coverage":     c.RequireFullCoverage,
 	}), nil
 }
@@ -0,0 +1,38 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/influxdata/influxdb/services/storage"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	// Parse configuration.
+	var c storage.Config
+	if _, err := toml.Decode(`
+enabled = true
+bind-address = ":8083"
+max-concurrent-reads = 4
+reject-excess-reads = true
+reject-unknown-group-keys = true
+require-full-coverage = true
+`, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate configuration.
+	if !c.Enabled {
+		t.Fatalf("unexpected enabled: %v", c.Enabled)
+	} else if c.BindAddress != ":8083" {
+		t.Fatalf("unexpected bind address: %s", c.BindAddress)
+	} else if c.MaxConcurrentReads != 4 {
+		t.Fatalf("unexpected max concurrent reads: %d", c.MaxConcurrentReads)
+	} else if !c.RejectExcessReads {
+		t.Fatalf("unexpected reject excess reads: %v", c.RejectExcessReads)
+	} else if !c.RejectUnknownGroupKeys {
+		t.Fatalf("unexpected reject unknown group keys: %v", c.RejectUnknownGroupKeys)
+	} else if !c.RequireFullCoverage {
+		t.Fatalf("unexpected require full coverage: %v", c.RequireFullCoverage)
+	}
+}
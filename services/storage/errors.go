@@ -0,0 +1,13 @@
+package storage
+
+import "fmt"
+
+// ShardError is returned when reading from a specific shard fails.
+type ShardError struct {
+	ShardID uint64
+	Err     error
+}
+
+func (e *ShardError) Error() string {
+	return fmt.Sprintf("shard %d: %v", e.ShardID, e.Err)
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "storage"
+	metricsSubsystem = "reads"
+)
+
+// Values of the method label identifying the Store method which served a
+// request.
+const (
+	methodRead                 = "read"
+	methodReadFieldKeys        = "read_field_keys"
+	methodReadMeasurementNames = "read_measurement_names"
+	methodSeriesCardinality    = "series_cardinality"
+	methodTagKeySeriesCounts   = "tag_key_series_counts"
+	methodTagKeyFirstSeen      = "tag_key_first_seen"
+	methodReadTagKeys          = "read_tag_keys"
+)
+
+// readMetrics holds the metrics collected for the read methods of a Store. A
+// nil *readMetrics collects nothing.
+type readMetrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	shards   *prometheus.GaugeVec
+}
+
+func newReadMetrics() *readMetrics {
+	labels := []string{"method"}
+	return &readMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "requests_total",
+			Help:      "Number of read requests.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "errors_total",
+			Help:      "Number of read requests which returned an error.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "duration_seconds",
+			Help:      "Time taken to serve read requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		shards: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "shards_scanned",
+			Help:      "Number of shards selected by the most recent read request.",
+		}, labels),
+	}
+}
+
+// PrometheusCollectors returns the collectors for all metrics in m.
+func (m *readMetrics) PrometheusCollectors() []prometheus.Collector {
+	if m == nil {
+		return nil
+	}
+	return []prometheus.Collector{m.requests, m.errors, m.duration, m.shards}
+}
+
+// register registers the collectors of m with reg. Any collector which is
+// already registered is replaced in m by the existing one.
+func (m *readMetrics) register(reg prometheus.Registerer) {
+	m.requests = registerCollector(reg, m.requests).(*prometheus.CounterVec)
+	m.errors = registerCollector(reg, m.errors).(*prometheus.CounterVec)
+	m.duration = registerCollector(reg, m.duration).(*prometheus.HistogramVec)
+	m.shards = registerCollector(reg, m.shards).(*prometheus.GaugeVec)
+}
+
+func registerCollector(reg prometheus.Registerer, c prometheus.Collector) prometheus.Collector {
+	if err := reg.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+		panic(err)
+	}
+	return c
+}
+
+// observe records a request to method which started at start and completed
+// with err. For Read, the duration does not include consuming the ResultSet.
+func (m *readMetrics) observe(method string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	m.requests.WithLabelValues(method).Inc()
+	if err != nil {
+		m.errors.WithLabelValues(method).Inc()
+	}
+	m.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// setShards records the number of shards selected by a request to method.
+func (m *readMetrics) setShards(method string, n int) {
+	if m == nil {
+		return
+	}
+	m.shards.WithLabelValues(method).Set(float64(n))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/opentracing/opentracing-go"
 )
 
 type readRequest struct {
@@ -16,29 +17,58 @@ type readRequest struct {
 }
 
 type ResultSet struct {
-	req readRequest
-	cur seriesCursor
-	row seriesRow
+	req     readRequest
+	cur     seriesCursor
+	row     seriesRow
+	err     error
+	release func()
+
+	span   opentracing.Span // finished by Close
+	series int              // number of series read
 }
 
 func (r *ResultSet) Close() {
 	r.row.query = nil
 	r.cur.Close()
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+	if r.span != nil {
+		r.span.SetTag("series_count", r.series)
+		r.span.Finish()
+		r.span = nil
+	}
 }
 
 func (r *ResultSet) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
 	row := r.cur.Next()
 	if row == nil {
 		return false
 	}
 
 	r.row = *row
+	r.series++
 
 	return true
 }
 
+// Err returns the first error encountered opening a series cursor.
+func (r *ResultSet) Err() error {
+	return r.err
+}
+
 func (r *ResultSet) Cursor() tsdb.Cursor {
-	cur := newMultiShardBatchCursor(r.req.ctx, r.row, &r.req)
+	cur, err := newMultiShardBatchCursor(r.req.ctx, r.row, &r.req)
+	if err != nil {
+		r.err = err
+		return nil
+	}
+
 	if r.req.aggregate != nil {
 		cur = newAggregateBatchCursor(r.req.ctx, r.req.aggregate, cur)
 	}
@@ -142,6 +142,16 @@ func (r *rpcService) Read(req *ReadRequest, stream Storage_ReadServer) error {
 		if w.err != nil {
 			return w.err
 		}
+
+		if err := cur.Err(); err != nil {
+			r.Logger.Error("Cursor failed", zap.Error(err))
+			return err
+		}
+	}
+
+	if err := rs.Err(); err != nil {
+		r.Logger.Error("ResultSet failed", zap.Error(err))
+		return err
 	}
 
 	w.flushFrames()
@@ -59,7 +59,7 @@ type indexSeriesCursor struct {
 }
 
 func newIndexSeriesCursor(ctx context.Context, req *ReadRequest, shards []*tsdb.Shard) (*indexSeriesCursor, error) {
-	queries, err := tsdb.CreateCursorIterators(ctx, shards)
+	queries, err := createCursorIterators(ctx, shards)
 	if err != nil {
 		return nil, err
 	}
@@ -223,6 +223,22 @@ func (c *indexSeriesCursor) Err() error {
 	return c.err
 }
 
+// newLimitGroupSeriesCursor applies the series limit, offset and grouping of
+// req to cur. SeriesLimit and SeriesOffset count distinct series in the order
+// of the index, so the limit is applied before the selected series are
+// grouped and can never drop a group partway through.
+func newLimitGroupSeriesCursor(ctx context.Context, cur seriesCursor, req *ReadRequest) seriesCursor {
+	if req.SeriesLimit > 0 || req.SeriesOffset > 0 {
+		cur = newLimitSeriesCursor(ctx, cur, req.SeriesLimit, req.SeriesOffset)
+	}
+
+	if len(req.Grouping) > 0 {
+		cur = newGroupSeriesCursor(ctx, cur, req.Grouping, req.GroupMode)
+	}
+
+	return cur
+}
+
 type limitSeriesCursor struct {
 	seriesCursor
 	n, o, c uint64
@@ -242,7 +258,8 @@ func (c *limitSeriesCursor) Next() *seriesRow {
 		c.o = 0
 	}
 
-	if c.c >= c.n {
+	// a limit of zero only applies the offset
+	if c.n > 0 && c.c >= c.n {
 		return nil
 	}
 	c.c++
@@ -254,11 +271,12 @@ type groupSeriesCursor struct {
 	ctx  context.Context
 	rows []seriesRow
 	keys [][]byte
+	mode ReadRequest_Group
 	f    bool
 }
 
-func newGroupSeriesCursor(ctx context.Context, cur seriesCursor, keys []string) *groupSeriesCursor {
-	g := &groupSeriesCursor{seriesCursor: cur, ctx: ctx}
+func newGroupSeriesCursor(ctx context.Context, cur seriesCursor, keys []string, mode ReadRequest_Group) *groupSeriesCursor {
+	g := &groupSeriesCursor{seriesCursor: cur, ctx: ctx, mode: mode}
 
 	g.keys = make([][]byte, 0, len(keys))
 	for _, k := range keys {
@@ -296,6 +314,25 @@ func (c *groupSeriesCursor) sort() {
 		row = c.seriesCursor.Next()
 	}
 
+	if c.mode == GroupModeExcept {
+		sortGroupExcept(rows, c.keys)
+	} else {
+		c.sortGroupBy(rows)
+	}
+
+	if span != nil {
+		span.SetTag("rows", len(rows))
+	}
+
+	c.rows = rows
+
+	// free early
+	c.seriesCursor.Close()
+	c.f = true
+}
+
+// sortGroupBy sorts rows by the values of the group keys.
+func (c *groupSeriesCursor) sortGroupBy(rows []seriesRow) {
 	sort.Slice(rows, func(i, j int) bool {
 		for _, k := range c.keys {
 			ik := rows[i].tags.Get(k)
@@ -309,16 +346,54 @@ func (c *groupSeriesCursor) sort() {
 
 		return false
 	})
+}
 
-	if span != nil {
-		span.SetTag("rows", len(rows))
+// sortGroupExcept sorts rows by all of their tags other than the keys in
+// except, so series which only differ by the excluded keys are adjacent. Rows
+// of the same group keep their index order.
+func sortGroupExcept(rows []seriesRow, except [][]byte) {
+	s := groupExceptSorter{rows: rows, keys: make([][]byte, len(rows))}
+	for i := range rows {
+		s.keys[i] = groupExceptKey(rows[i].tags, except)
 	}
+	sort.Stable(&s)
+}
 
-	c.rows = rows
+// groupExceptKey returns the tags of a series, other than the keys in except,
+// encoded so that comparing two group keys compares the tags in key order.
+func groupExceptKey(tags models.Tags, except [][]byte) []byte {
+	var key []byte
+	for _, t := range tags {
+		if containsKey(except, t.Key) {
+			continue
+		}
+		key = append(key, t.Key...)
+		key = append(key, 0)
+		key = append(key, t.Value...)
+		key = append(key, 0)
+	}
+	return key
+}
 
-	// free early
-	c.seriesCursor.Close()
-	c.f = true
+func containsKey(keys [][]byte, key []byte) bool {
+	for _, k := range keys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+type groupExceptSorter struct {
+	rows []seriesRow
+	keys [][]byte
+}
+
+func (s *groupExceptSorter) Len() int           { return len(s.rows) }
+func (s *groupExceptSorter) Less(i, j int) bool { return bytes.Compare(s.keys[i], s.keys[j]) == -1 }
+func (s *groupExceptSorter) Swap(i, j int) {
+	s.rows[i], s.rows[j] = s.rows[j], s.rows[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
 }
 
 func isBooleanLiteral(expr influxql.Expr) bool {
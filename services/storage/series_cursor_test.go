@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -60,3 +61,89 @@ func (itr *floatIterator) Next() (*tsdb.SeriesCursorRow, error) {
 	itr.Points = itr.Points[1:]
 	return v, nil
 }
+
+func TestLimitGroupSeriesCursor(t *testing.T) {
+	// rows in index order
+	keys := []string{
+		"cpu,dc=1,host=c",
+		"cpu,dc=2,host=a",
+		"cpu,dc=3,host=b",
+		"cpu,dc=4,host=a",
+	}
+
+	cases := []struct {
+		n   string
+		req ReadRequest
+		exp []string
+	}{
+		{
+			n:   "limit",
+			req: ReadRequest{SeriesLimit: 2},
+			exp: []string{"cpu,dc=1,host=c", "cpu,dc=2,host=a"},
+		},
+		{
+			n:   "offset",
+			req: ReadRequest{SeriesOffset: 3},
+			exp: []string{"cpu,dc=4,host=a"},
+		},
+		{
+			n:   "group",
+			req: ReadRequest{Grouping: []string{"host", "dc"}},
+			exp: []string{"cpu,dc=2,host=a", "cpu,dc=4,host=a", "cpu,dc=3,host=b", "cpu,dc=1,host=c"},
+		},
+		{
+			n:   "group except",
+			req: ReadRequest{Grouping: []string{"dc"}, GroupMode: GroupModeExcept},
+			exp: []string{"cpu,dc=2,host=a", "cpu,dc=4,host=a", "cpu,dc=3,host=b", "cpu,dc=1,host=c"},
+		},
+		{
+			n:   "group except all keys",
+			req: ReadRequest{Grouping: []string{"dc", "host"}, GroupMode: GroupModeExcept},
+			exp: []string{"cpu,dc=1,host=c", "cpu,dc=2,host=a", "cpu,dc=3,host=b", "cpu,dc=4,host=a"},
+		},
+		{
+			n:   "limit and offset count series before grouping",
+			req: ReadRequest{Grouping: []string{"host"}, SeriesLimit: 2, SeriesOffset: 1},
+			exp: []string{"cpu,dc=2,host=a", "cpu,dc=3,host=b"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			var rows []seriesRow
+			for _, k := range keys {
+				tags := models.ParseTags([]byte(k))
+				rows = append(rows, seriesRow{name: []byte("cpu"), stags: tags, tags: tags})
+			}
+
+			cur := newLimitGroupSeriesCursor(context.Background(), &sliceSeriesCursor{rows: rows}, &tc.req)
+
+			var got []string
+			for row := cur.Next(); row != nil; row = cur.Next() {
+				got = append(got, string(models.MakeKey(row.name, row.stags)))
+			}
+
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected series; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+// sliceSeriesCursor is a seriesCursor which reads from a slice.
+type sliceSeriesCursor struct {
+	rows []seriesRow
+}
+
+func (c *sliceSeriesCursor) Close()     {}
+func (c *sliceSeriesCursor) Err() error { return nil }
+
+func (c *sliceSeriesCursor) Next() *seriesRow {
+	if len(c.rows) == 0 {
+		return nil
+	}
+
+	row := &c.rows[0]
+	c.rows = c.rows[1:]
+	return row
+}
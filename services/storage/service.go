@@ -5,6 +5,7 @@ import (
 
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -15,10 +16,15 @@ type StorageMetaClient interface {
 
 // Service manages the listener and handler for an HTTP endpoint.
 type Service struct {
-	addr           string
-	yarpc          *yarpcServer
-	loggingEnabled bool
-	logger         *zap.Logger
+	addr               string
+	yarpc              *yarpcServer
+	loggingEnabled     bool
+	maxConcurrentReads int
+	rejectExcessReads  bool
+	rejectGroupKeys    bool
+	requireCoverage    bool
+	logger             *zap.Logger
+	registerer         prometheus.Registerer
 
 	Store      *Store
 	TSDBStore  *tsdb.Store
@@ -28,9 +34,14 @@ type Service struct {
 // NewService returns a new instance of Service.
 func NewService(c Config) *Service {
 	s := &Service{
-		addr:           c.BindAddress,
-		loggingEnabled: c.LogEnabled,
-		logger:         zap.NewNop(),
+		addr:               c.BindAddress,
+		loggingEnabled:     c.LogEnabled,
+		maxConcurrentReads: c.MaxConcurrentReads,
+		rejectExcessReads:  c.RejectExcessReads,
+		rejectGroupKeys:    c.RejectUnknownGroupKeys,
+		requireCoverage:    c.RequireFullCoverage,
+		logger:             zap.NewNop(),
+		registerer:         prometheus.DefaultRegisterer,
 	}
 
 	return s
@@ -45,16 +56,11 @@ func (s *Service) WithLogger(log *zap.Logger) {
 func (s *Service) Open() error {
 	s.logger.Info("Starting storage service")
 
-	store := NewStore()
-	store.TSDBStore = s.TSDBStore
-	store.MetaClient = s.MetaClient
-	store.Logger = s.logger
-
 	yarpc := &yarpcServer{
 		addr:           s.addr,
 		loggingEnabled: s.loggingEnabled,
 		logger:         s.logger,
-		store:          store,
+		store:          s.newStore(),
 	}
 	if err := yarpc.Open(); err != nil {
 		return err
@@ -65,6 +71,22 @@ func (s *Service) Open() error {
 	return nil
 }
 
+// newStore returns the Store which serves the requests of the service. Its
+// metrics are registered with the default Prometheus registry, which is served
+// by the HTTP service at /metrics.
+func (s *Service) newStore() *Store {
+	store := NewStore()
+	store.TSDBStore = s.TSDBStore
+	store.MetaClient = s.MetaClient
+	store.Logger = s.logger
+	store.MaxConcurrentReads = s.maxConcurrentReads
+	store.RejectExcessReads = s.rejectExcessReads
+	store.RejectUnknownGroupKeys = s.rejectGroupKeys
+	store.RequireFullCoverage = s.requireCoverage
+	store.WithMetrics(s.registerer)
+	return store
+}
+
 func (s *Service) Close() error {
 	if s.yarpc != nil {
 		s.yarpc.Close()
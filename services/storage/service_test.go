@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestService_newStore_Metrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	// Both services register the same metrics, as do the services of
+	// multiple servers in one process.
+	for i := 0; i < 2; i++ {
+		svc := NewService(NewConfig())
+		svc.MetaClient = newMetaClient()
+		svc.registerer = reg
+
+		s := svc.newStore()
+		if _, err := s.Read(context.Background(), &ReadRequest{Database: "db0"}); err != nil {
+			t.Fatal("Read", err)
+		}
+	}
+
+	got := gatherReadMetrics(t, reg)
+	exp := map[string]float64{
+		"storage_reads_requests_total":   2,
+		"storage_reads_duration_seconds": 2,
+		"storage_reads_shards_scanned":   0,
+	}
+	if !cmp.Equal(got, exp) {
+		t.Errorf("unexpected metrics; -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+}
+
+func TestService_newStore_Config(t *testing.T) {
+	c := NewConfig()
+	c.MaxConcurrentReads = 4
+	c.RejectExcessReads = true
+	c.RejectUnknownGroupKeys = true
+	c.RequireFullCoverage = true
+
+	svc := NewService(c)
+	svc.registerer = prometheus.NewRegistry()
+
+	s := svc.newStore()
+	if s.MaxConcurrentReads != 4 {
+		t.Errorf("unexpected MaxConcurrentReads: got %d, exp %d", s.MaxConcurrentReads, 4)
+	}
+	if !s.RejectExcessReads {
+		t.Errorf("unexpected RejectExcessReads: got %v, exp %v", s.RejectExcessReads, true)
+	}
+	if !s.RejectUnknownGroupKeys {
+		t.Errorf("unexpected RejectUnknownGroupKeys: got %v, exp %v", s.RejectUnknownGroupKeys, true)
+	}
+	if !s.RequireFullCoverage {
+		t.Errorf("unexpected RequireFullCoverage: got %v, exp %v", s.RequireFullCoverage, true)
+	}
+}
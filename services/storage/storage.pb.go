@@ -62,6 +62,27 @@ func (x ReadRequest_Type) String() string {
 }
 func (ReadRequest_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorStorage, []int{0, 0} }
 
+type ReadRequest_Group int32
+
+const (
+	GroupModeBy     ReadRequest_Group = 0
+	GroupModeExcept ReadRequest_Group = 1
+)
+
+var ReadRequest_Group_name = map[int32]string{
+	0: "GROUP_BY",
+	1: "GROUP_EXCEPT",
+}
+var ReadRequest_Group_value = map[string]int32{
+	"GROUP_BY":     0,
+	"GROUP_EXCEPT": 1,
+}
+
+func (x ReadRequest_Group) String() string {
+	return proto.EnumName(ReadRequest_Group_name, int32(x))
+}
+func (ReadRequest_Group) EnumDescriptor() ([]byte, []int) { return fileDescriptorStorage, []int{0, 1} }
+
 type Aggregate_AggregateType int32
 
 const (
@@ -156,13 +177,19 @@ type ReadRequest struct {
 	Descending bool `protobuf:"varint,3,opt,name=descending,proto3" json:"descending,omitempty"`
 	// Grouping specifies a list of tags used to order the data
 	Grouping []string `protobuf:"bytes,4,rep,name=grouping" json:"grouping,omitempty"`
+	// GroupMode specifies whether series are grouped by the tag keys in Grouping, or by all of their
+	// tag keys except those in Grouping.
+	GroupMode ReadRequest_Group `protobuf:"varint,13,opt,name=group_mode,json=groupMode,proto3,enum=storage.ReadRequest_Group" json:"group_mode,omitempty"`
 	// Aggregate specifies an optional aggregate to apply to the data.
 	// TODO(sgc): switch to slice for multiple aggregates in a single request
 	Aggregate *Aggregate `protobuf:"bytes,9,opt,name=aggregate" json:"aggregate,omitempty"`
 	Predicate *Predicate `protobuf:"bytes,5,opt,name=predicate" json:"predicate,omitempty"`
 	// SeriesLimit determines the maximum number of series to be returned for the request. Specify 0 for no limit.
+	// SeriesLimit and SeriesOffset count series in index order and are applied before Grouping, so a
+	// grouped response contains only the selected series.
 	SeriesLimit uint64 `protobuf:"varint,6,opt,name=series_limit,json=seriesLimit,proto3" json:"series_limit,omitempty"`
-	// SeriesOffset determines how many series to skip before processing the request.
+	// SeriesOffset determines how many series to skip before processing the request. It applies even when
+	// SeriesLimit is 0.
 	SeriesOffset uint64 `protobuf:"varint,7,opt,name=series_offset,json=seriesOffset,proto3" json:"series_offset,omitempty"`
 	// PointsLimit determines the maximum number of values per series to be returned for the request.
 	// Specify 0 for no limit.
@@ -570,6 +597,7 @@ func init() {
 	proto.RegisterType((*HintsResponse)(nil), "storage.HintsResponse")
 	proto.RegisterType((*TimestampRange)(nil), "storage.TimestampRange")
 	proto.RegisterEnum("storage.ReadRequest_Type", ReadRequest_Type_name, ReadRequest_Type_value)
+	proto.RegisterEnum("storage.ReadRequest_Group", ReadRequest_Group_name, ReadRequest_Group_value)
 	proto.RegisterEnum("storage.Aggregate_AggregateType", Aggregate_AggregateType_name, Aggregate_AggregateType_value)
 	proto.RegisterEnum("storage.ReadResponse_FrameType", ReadResponse_FrameType_name, ReadResponse_FrameType_value)
 	proto.RegisterEnum("storage.ReadResponse_DataType", ReadResponse_DataType_name, ReadResponse_DataType_value)
@@ -691,6 +719,11 @@ func (m *ReadRequest) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintStorage(dAtA, i, uint64(len(m.OrgID)))
 		i += copy(dAtA[i:], m.OrgID)
 	}
+	if m.GroupMode != 0 {
+		dAtA[i] = 0x68
+		i++
+		i = encodeVarintStorage(dAtA, i, uint64(m.GroupMode))
+	}
 	return i, nil
 }
 
@@ -1371,6 +1404,9 @@ func (m *ReadRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovStorage(uint64(l))
 	}
+	if m.GroupMode != 0 {
+		n += 1 + sovStorage(uint64(m.GroupMode))
+	}
 	return n
 }
 
@@ -2029,6 +2065,25 @@ func (m *ReadRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.OrgID = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupMode", wireType)
+			}
+			m.GroupMode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GroupMode |= (ReadRequest_Group(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStorage(dAtA[iNdEx:])
@@ -3883,88 +3938,92 @@ var (
 func init() { proto.RegisterFile("storage.proto", fileDescriptorStorage) }
 
 var fileDescriptorStorage = []byte{
-	// 1316 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xbf, 0x6f, 0xdb, 0xc6,
-	0x17, 0x27, 0x2d, 0x4a, 0xb6, 0x9e, 0x24, 0x9b, 0xbe, 0x38, 0xfe, 0xea, 0xcb, 0x34, 0x12, 0xa3,
-	0x21, 0x55, 0x87, 0x28, 0x81, 0xda, 0xa2, 0x69, 0x83, 0x02, 0xb5, 0x12, 0xc5, 0x56, 0x63, 0x53,
-	0xc1, 0x49, 0x06, 0x3a, 0x14, 0x70, 0x4f, 0xd6, 0x89, 0x21, 0x2a, 0x91, 0x2c, 0x49, 0x15, 0xf1,
-	0xd6, 0xb1, 0x10, 0x3a, 0x74, 0xe8, 0xaa, 0x29, 0x7f, 0x43, 0xbb, 0x74, 0xeb, 0x94, 0xb1, 0x63,
-	0x27, 0xa3, 0x55, 0xff, 0x91, 0xe2, 0xee, 0x48, 0x8a, 0x94, 0xe5, 0x00, 0x5e, 0x04, 0xbe, 0xf7,
-	0x3e, 0xef, 0xf3, 0x7e, 0xdc, 0x7b, 0x77, 0x82, 0x92, 0x1f, 0x38, 0x1e, 0x31, 0x69, 0xc3, 0xf5,
-	0x9c, 0xc0, 0x41, 0x9b, 0xa1, 0xa8, 0x3d, 0x30, 0xad, 0xe0, 0xd5, 0x74, 0xd0, 0x38, 0x77, 0x26,
-	0x0f, 0x4d, 0xc7, 0x74, 0x1e, 0x72, 0xfb, 0x60, 0x3a, 0xe2, 0x12, 0x17, 0xf8, 0x97, 0xf0, 0xd3,
-	0xee, 0x98, 0x8e, 0x63, 0x8e, 0xe9, 0x12, 0x45, 0x27, 0x6e, 0x70, 0x11, 0x1a, 0x9b, 0x09, 0x2e,
-	0xcb, 0x1e, 0x8d, 0xa7, 0xaf, 0x87, 0x24, 0x20, 0x0f, 0x2f, 0x88, 0xe7, 0x9e, 0x8b, 0x5f, 0xc1,
-	0xc7, 0x3f, 0x43, 0x9f, 0x1d, 0xd7, 0xa3, 0x43, 0xeb, 0x9c, 0x04, 0x61, 0x66, 0xb5, 0x37, 0x39,
-	0x28, 0x60, 0x4a, 0x86, 0x98, 0x7e, 0x37, 0xa5, 0x7e, 0x80, 0x34, 0xd8, 0x62, 0x2c, 0x03, 0xe2,
-	0xd3, 0xb2, 0xac, 0xcb, 0xf5, 0x3c, 0x8e, 0x65, 0xf4, 0x15, 0xec, 0x04, 0xd6, 0x84, 0xfa, 0x01,
-	0x99, 0xb8, 0x67, 0x1e, 0xb1, 0x4d, 0x5a, 0xde, 0xd0, 0xe5, 0x7a, 0xa1, 0xf9, 0xbf, 0x46, 0x54,
-	0x6e, 0x3f, 0xb2, 0x63, 0x66, 0x6e, 0xed, 0xbf, 0xbd, 0xac, 0x4a, 0x8b, 0xcb, 0xea, 0x76, 0x5a,
-	0x8f, 0xb7, 0x83, 0x94, 0x8c, 0x2a, 0x00, 0x43, 0xea, 0x9f, 0x53, 0x7b, 0x68, 0xd9, 0x66, 0x39,
-	0xa3, 0xcb, 0xf5, 0x2d, 0x9c, 0xd0, 0xb0, 0xac, 0x4c, 0xcf, 0x99, 0xba, 0xcc, 0xaa, 0xe8, 0x19,
-	0x96, 0x55, 0x24, 0xa3, 0x47, 0x90, 0x8f, 0x8b, 0x2a, 0x67, 0x79, 0x3e, 0x28, 0xce, 0xe7, 0x65,
-	0x64, 0xc1, 0x4b, 0x10, 0x6a, 0x42, 0xd1, 0xa7, 0x9e, 0x45, 0xfd, 0xb3, 0xb1, 0x35, 0xb1, 0x82,
-	0x72, 0x4e, 0x97, 0xeb, 0x4a, 0x6b, 0x67, 0x71, 0x59, 0x2d, 0xf4, 0xb8, 0xfe, 0x98, 0xa9, 0x71,
-	0xc1, 0x5f, 0x0a, 0xe8, 0x63, 0x28, 0x85, 0x3e, 0xce, 0x68, 0xe4, 0xd3, 0xa0, 0xbc, 0xc9, 0x9d,
-	0xd4, 0xc5, 0x65, 0xb5, 0x28, 0x9c, 0xba, 0x5c, 0x8f, 0x43, 0x6a, 0x21, 0xb1, 0x50, 0xae, 0x63,
-	0xd9, 0x41, 0x14, 0x6a, 0x6b, 0x19, 0xea, 0x25, 0xd7, 0x87, 0xa1, 0xdc, 0xa5, 0xc0, 0x0a, 0x22,
-	0xa6, 0xe9, 0x51, 0x93, 0x15, 0x94, 0x5f, 0x29, 0xe8, 0x20, 0xb2, 0xe0, 0x25, 0x08, 0x7d, 0x01,
-	0xd9, 0xc0, 0x23, 0xe7, 0xb4, 0x0c, 0x7a, 0xa6, 0x5e, 0x68, 0x56, 0x63, 0x74, 0xe2, 0x64, 0x1b,
-	0x7d, 0x86, 0x68, 0xdb, 0x81, 0x77, 0xd1, 0xca, 0x2f, 0x2e, 0xab, 0x59, 0x2e, 0x63, 0xe1, 0x88,
-	0x4e, 0xa0, 0xe8, 0x09, 0xdc, 0x59, 0x70, 0xe1, 0xd2, 0x72, 0x41, 0x97, 0xeb, 0xdb, 0xcd, 0xff,
-	0xaf, 0x27, 0xba, 0x70, 0xa9, 0x28, 0x21, 0xd4, 0x30, 0x05, 0x2e, 0x78, 0x4b, 0x01, 0xe9, 0x90,
-	0x73, 0x3c, 0xf3, 0xcc, 0x1a, 0x96, 0x8b, 0x6c, 0x86, 0x44, 0xc0, 0xae, 0x67, 0x76, 0x9e, 0xe1,
-	0xac, 0xe3, 0x99, 0x9d, 0xa1, 0xf6, 0x18, 0x60, 0x99, 0x10, 0x52, 0x21, 0xf3, 0x2d, 0xbd, 0x08,
-	0x07, 0x8e, 0x7d, 0xa2, 0x3d, 0xc8, 0x7e, 0x4f, 0xc6, 0x53, 0x31, 0x61, 0x79, 0x2c, 0x84, 0xcf,
-	0x36, 0x1e, 0xcb, 0x35, 0x0f, 0x14, 0x1e, 0xa3, 0x09, 0xa5, 0x5e, 0xc7, 0x38, 0x3c, 0x6e, 0x9f,
-	0xf5, 0xdb, 0xc6, 0x81, 0xd1, 0x57, 0x25, 0xad, 0x3a, 0x9b, 0xeb, 0x77, 0x12, 0xa9, 0x32, 0x5c,
-	0xcf, 0xb2, 0xcd, 0x31, 0xed, 0x53, 0x9b, 0xd8, 0xac, 0xb5, 0xc5, 0x93, 0xd3, 0xe3, 0x7e, 0x27,
-	0x72, 0x91, 0xb5, 0xca, 0x6c, 0xae, 0x6b, 0x2b, 0x2e, 0x27, 0xd3, 0x71, 0x60, 0x09, 0x0f, 0x4d,
-	0xf9, 0xf1, 0x4d, 0x45, 0xaa, 0xfd, 0x2e, 0x43, 0x3e, 0xee, 0x3c, 0xfa, 0x08, 0x14, 0xde, 0x24,
-	0x99, 0x37, 0x49, 0xbf, 0x7a, 0x36, 0xcb, 0x2f, 0xde, 0x1a, 0x8e, 0xae, 0xbd, 0x86, 0x52, 0x4a,
-	0x8d, 0xaa, 0xa0, 0x18, 0x5d, 0xa3, 0xad, 0x4a, 0xda, 0xed, 0xd9, 0x5c, 0xdf, 0x4d, 0x19, 0x0d,
-	0xc7, 0xa6, 0xe8, 0x2e, 0x64, 0x7a, 0xa7, 0x27, 0xaa, 0xac, 0xed, 0xcd, 0xe6, 0xba, 0x9a, 0xb2,
-	0xf7, 0xa6, 0x13, 0x74, 0x0f, 0xb2, 0x4f, 0xbb, 0xa7, 0x46, 0x5f, 0xdd, 0xd0, 0xf6, 0x67, 0x73,
-	0x1d, 0xa5, 0x00, 0x4f, 0x9d, 0x69, 0x9c, 0xfd, 0x03, 0xc8, 0xf4, 0x89, 0x99, 0x6c, 0x72, 0x71,
-	0x4d, 0x93, 0x8b, 0x61, 0x93, 0x6b, 0xbf, 0x14, 0xa0, 0x28, 0x3a, 0xe2, 0xbb, 0x8e, 0xed, 0x53,
-	0xf4, 0x29, 0xe4, 0x46, 0x1e, 0x99, 0x50, 0xbf, 0x2c, 0xf3, 0xf9, 0xba, 0xb3, 0x32, 0x16, 0x02,
-	0xd6, 0x78, 0xce, 0x30, 0x2d, 0x85, 0xad, 0x3c, 0x0e, 0x1d, 0xb4, 0x3f, 0x14, 0xc8, 0x72, 0x3d,
-	0x7a, 0x02, 0x39, 0xb1, 0x19, 0x3c, 0x81, 0x42, 0xf3, 0xde, 0x7a, 0x12, 0xb1, 0x4b, 0xdc, 0xe5,
-	0x48, 0xc2, 0xa1, 0x0b, 0xfa, 0x1a, 0x8a, 0xa3, 0xb1, 0x43, 0x82, 0x33, 0xb1, 0x27, 0xe1, 0xb5,
-	0x73, 0xff, 0x9a, 0x3c, 0x18, 0x52, 0x6c, 0x97, 0x48, 0x89, 0xcf, 0x6a, 0x42, 0x7b, 0x24, 0xe1,
-	0xc2, 0x68, 0x29, 0xa2, 0x21, 0x6c, 0x5b, 0x76, 0x40, 0x4d, 0xea, 0x45, 0xfc, 0x19, 0xce, 0x5f,
-	0x5f, 0xcf, 0xdf, 0x11, 0xd8, 0x64, 0x84, 0xdd, 0xc5, 0x65, 0xb5, 0x94, 0xd2, 0x1f, 0x49, 0xb8,
-	0x64, 0x25, 0x15, 0xe8, 0x15, 0xec, 0x4c, 0x6d, 0xdf, 0x32, 0x6d, 0x3a, 0x8c, 0xc2, 0x28, 0x3c,
-	0xcc, 0x07, 0xeb, 0xc3, 0x9c, 0x86, 0xe0, 0x64, 0x1c, 0xc4, 0xee, 0xd2, 0xb4, 0xe1, 0x48, 0xc2,
-	0xdb, 0xd3, 0x94, 0x86, 0xd5, 0x33, 0x70, 0x9c, 0x31, 0x25, 0x76, 0x14, 0x28, 0xfb, 0xae, 0x7a,
-	0x5a, 0x02, 0x7b, 0xa5, 0x9e, 0x94, 0x9e, 0xd5, 0x33, 0x48, 0x2a, 0xd0, 0x37, 0xec, 0x91, 0xf3,
-	0x2c, 0xdb, 0x8c, 0x82, 0xe4, 0x78, 0x90, 0xf7, 0xaf, 0x39, 0x57, 0x0e, 0x4d, 0xc6, 0x10, 0x57,
-	0x67, 0x42, 0x7d, 0x24, 0xe1, 0xa2, 0x9f, 0x90, 0x5b, 0x39, 0x50, 0xd8, 0xdb, 0xa3, 0x79, 0x50,
-	0x48, 0x8c, 0x05, 0xba, 0x0f, 0x4a, 0x40, 0xcc, 0x68, 0x18, 0x8b, 0xcb, 0xb7, 0x87, 0x98, 0xe1,
-	0xf4, 0x71, 0x3b, 0x7a, 0x02, 0x79, 0xe6, 0x2e, 0x2e, 0xb4, 0x0d, 0xbe, 0xab, 0x95, 0xf5, 0xc9,
-	0x3d, 0x23, 0x01, 0xe1, 0x9b, 0xca, 0xdf, 0x3a, 0xf6, 0xa5, 0x7d, 0x09, 0xea, 0xea, 0x1c, 0xb1,
-	0x57, 0x2a, 0x7e, 0xb7, 0x44, 0x78, 0x15, 0x27, 0x34, 0x68, 0x1f, 0x72, 0x7c, 0x83, 0xd8, 0x7c,
-	0x66, 0xea, 0x32, 0x0e, 0x25, 0xed, 0x18, 0xd0, 0xd5, 0x99, 0xb9, 0x21, 0x5b, 0x26, 0x66, 0x3b,
-	0x81, 0x5b, 0x6b, 0x46, 0xe3, 0x86, 0x74, 0x4a, 0x32, 0xb9, 0xab, 0x03, 0x70, 0x43, 0xb6, 0xad,
-	0x98, 0xed, 0x05, 0xec, 0x5e, 0x39, 0xe9, 0x1b, 0x92, 0xe5, 0x23, 0xb2, 0x5a, 0x0f, 0xf2, 0x9c,
-	0x20, 0xbc, 0x2d, 0x73, 0xbd, 0x36, 0xee, 0xb4, 0x7b, 0xaa, 0xa4, 0xdd, 0x9a, 0xcd, 0xf5, 0x9d,
-	0xd8, 0x24, 0x66, 0x83, 0x01, 0x5e, 0x76, 0x3b, 0x46, 0xbf, 0xa7, 0xca, 0x2b, 0x00, 0x91, 0x4b,
-	0x78, 0x19, 0xfe, 0x26, 0xc3, 0x56, 0x74, 0xde, 0xe8, 0x3d, 0xc8, 0x3e, 0x3f, 0xee, 0x1e, 0xb0,
-	0xb7, 0x63, 0x77, 0x36, 0xd7, 0x4b, 0x91, 0x81, 0x1f, 0x3d, 0xd2, 0x61, 0xb3, 0x63, 0xf4, 0xdb,
-	0x87, 0x6d, 0x1c, 0x51, 0x46, 0xf6, 0xf0, 0x38, 0x51, 0x0d, 0xb6, 0x4e, 0x8d, 0x5e, 0xe7, 0xd0,
-	0x68, 0x3f, 0x53, 0x37, 0xc4, 0x35, 0x1d, 0x41, 0xa2, 0x33, 0x62, 0x2c, 0xad, 0x6e, 0xf7, 0xb8,
-	0x7d, 0x60, 0xa8, 0x99, 0x34, 0x4b, 0xd8, 0x77, 0x54, 0x81, 0x5c, 0xaf, 0x8f, 0x3b, 0xc6, 0xa1,
-	0xaa, 0x68, 0x68, 0x36, 0xd7, 0xb7, 0x23, 0x80, 0x68, 0x65, 0x98, 0xf8, 0x4f, 0x32, 0xec, 0x3d,
-	0x25, 0x2e, 0x19, 0x58, 0x63, 0x2b, 0xb0, 0xa8, 0x1f, 0x5f, 0xcf, 0x4f, 0x40, 0x39, 0x27, 0x6e,
-	0xb4, 0x0f, 0xcb, 0xfd, 0x5b, 0x07, 0x66, 0x4a, 0x9f, 0xbf, 0xb9, 0x98, 0x3b, 0x69, 0x9f, 0x40,
-	0x3e, 0x56, 0xdd, 0xe8, 0x19, 0xde, 0x81, 0xd2, 0x11, 0x6b, 0x6b, 0xc4, 0x5c, 0x7b, 0x0c, 0x2b,
-	0xff, 0xf2, 0x98, 0xb3, 0x1f, 0x10, 0x2f, 0xe0, 0x84, 0x19, 0x2c, 0x04, 0x16, 0x84, 0xda, 0x43,
-	0x4e, 0x98, 0xc1, 0xec, 0xb3, 0xf9, 0x97, 0x0c, 0x9b, 0x3d, 0x91, 0x34, 0x2b, 0x86, 0xad, 0x26,
-	0xda, 0x5b, 0xf7, 0xd7, 0x43, 0xbb, 0xbd, 0x76, 0x7f, 0x6b, 0xca, 0x0f, 0xbf, 0x96, 0xa5, 0x47,
-	0x32, 0x7a, 0x01, 0xc5, 0x64, 0xd1, 0x68, 0xbf, 0x21, 0xfe, 0x3f, 0x37, 0xa2, 0xff, 0xcf, 0x8d,
-	0x36, 0xfb, 0xff, 0xac, 0xdd, 0x7d, 0x67, 0x8f, 0x38, 0x9d, 0x8c, 0x3e, 0x87, 0x2c, 0x2f, 0xf0,
-	0x5a, 0x96, 0xfd, 0x98, 0x25, 0xdd, 0x08, 0xe6, 0xbe, 0xa1, 0xf1, 0x9c, 0x5a, 0x7b, 0x6f, 0xff,
-	0xa9, 0x48, 0x6f, 0x17, 0x15, 0xf9, 0xcf, 0x45, 0x45, 0xfe, 0x7b, 0x51, 0x91, 0x7f, 0xfe, 0xb7,
-	0x22, 0x0d, 0x72, 0x9c, 0xe9, 0xc3, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0xc3, 0xd6, 0xa7, 0xe4,
-	0x26, 0x0c, 0x00, 0x00,
+	// 1389 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0x4f, 0x8f, 0xda, 0x46,
+	0x1b, 0xb7, 0x17, 0xc3, 0xc2, 0x83, 0xd9, 0xf5, 0x4e, 0x36, 0xfb, 0xf2, 0x3a, 0x6f, 0xc0, 0x41,
+	0x7a, 0x53, 0x7a, 0x08, 0x89, 0x68, 0xab, 0xa6, 0x8d, 0x2a, 0x75, 0xd9, 0x38, 0xbb, 0x34, 0xbb,
+	0xb0, 0x1a, 0x58, 0x29, 0x95, 0x2a, 0x51, 0x2f, 0x0c, 0x8e, 0x55, 0xb0, 0x5d, 0xdb, 0x54, 0xe1,
+	0xd6, 0x63, 0x85, 0x7a, 0xe8, 0xa1, 0x57, 0x4e, 0xfd, 0x0c, 0xed, 0xa5, 0xb7, 0x9c, 0x72, 0xec,
+	0xb1, 0x27, 0xd4, 0xd2, 0x2f, 0x52, 0xcd, 0x8c, 0x6d, 0xcc, 0x2e, 0x89, 0xb4, 0x17, 0x6b, 0x9e,
+	0x7f, 0xbf, 0xe7, 0xff, 0x8c, 0xa1, 0xe0, 0x07, 0x8e, 0x67, 0x98, 0xa4, 0xe6, 0x7a, 0x4e, 0xe0,
+	0xa0, 0xed, 0x90, 0x54, 0x1f, 0x98, 0x56, 0xf0, 0x72, 0x72, 0x59, 0xeb, 0x3b, 0xe3, 0x87, 0xa6,
+	0x63, 0x3a, 0x0f, 0x99, 0xfc, 0x72, 0x32, 0x64, 0x14, 0x23, 0xd8, 0x89, 0xdb, 0xa9, 0x77, 0x4c,
+	0xc7, 0x31, 0x47, 0x64, 0xa5, 0x45, 0xc6, 0x6e, 0x30, 0x0d, 0x85, 0xf5, 0x04, 0x96, 0x65, 0x0f,
+	0x47, 0x93, 0x57, 0x03, 0x23, 0x30, 0x1e, 0x4e, 0x0d, 0xcf, 0xed, 0xf3, 0x2f, 0xc7, 0x63, 0xc7,
+	0xd0, 0x66, 0xd7, 0xf5, 0xc8, 0xc0, 0xea, 0x1b, 0x41, 0x18, 0x59, 0xe5, 0xf5, 0x36, 0xe4, 0x31,
+	0x31, 0x06, 0x98, 0x7c, 0x3b, 0x21, 0x7e, 0x80, 0x54, 0xc8, 0x52, 0x94, 0x4b, 0xc3, 0x27, 0x45,
+	0x51, 0x13, 0xab, 0x39, 0x1c, 0xd3, 0xe8, 0x05, 0xec, 0x06, 0xd6, 0x98, 0xf8, 0x81, 0x31, 0x76,
+	0x7b, 0x9e, 0x61, 0x9b, 0xa4, 0xb8, 0xa5, 0x89, 0xd5, 0x7c, 0xfd, 0x3f, 0xb5, 0x28, 0xdd, 0x6e,
+	0x24, 0xc7, 0x54, 0xdc, 0x38, 0x78, 0xb3, 0x28, 0x0b, 0xcb, 0x45, 0x79, 0x67, 0x9d, 0x8f, 0x77,
+	0x82, 0x35, 0x1a, 0x95, 0x00, 0x06, 0xc4, 0xef, 0x13, 0x7b, 0x60, 0xd9, 0x66, 0x31, 0xa5, 0x89,
+	0xd5, 0x2c, 0x4e, 0x70, 0x68, 0x54, 0xa6, 0xe7, 0x4c, 0x5c, 0x2a, 0x95, 0xb4, 0x14, 0x8d, 0x2a,
+	0xa2, 0xd1, 0x09, 0x00, 0x3b, 0xf7, 0xc6, 0xce, 0x80, 0x14, 0x0b, 0x9a, 0x58, 0xdd, 0xa9, 0xab,
+	0x71, 0x40, 0x89, 0xdc, 0x6a, 0xc7, 0x54, 0xad, 0x51, 0x58, 0x2e, 0xca, 0x39, 0x76, 0x3c, 0x73,
+	0x06, 0x04, 0xe7, 0xcc, 0xe8, 0x88, 0x1e, 0x41, 0x2e, 0x2e, 0x4f, 0x31, 0xcd, 0x32, 0x43, 0x31,
+	0xd0, 0x79, 0x24, 0xc1, 0x2b, 0x25, 0x54, 0x07, 0xd9, 0x27, 0x9e, 0x45, 0xfc, 0xde, 0xc8, 0x1a,
+	0x5b, 0x41, 0x31, 0xa3, 0x89, 0x55, 0xa9, 0xb1, 0xbb, 0x5c, 0x94, 0xf3, 0x1d, 0xc6, 0x3f, 0xa5,
+	0x6c, 0x9c, 0xf7, 0x57, 0x04, 0xfa, 0x08, 0x0a, 0xa1, 0x8d, 0x33, 0x1c, 0xfa, 0x24, 0x28, 0x6e,
+	0x33, 0x23, 0x65, 0xb9, 0x28, 0xcb, 0xdc, 0xa8, 0xcd, 0xf8, 0x58, 0xf6, 0x13, 0x14, 0x75, 0xe5,
+	0x3a, 0x96, 0x1d, 0x44, 0xae, 0xb2, 0x2b, 0x57, 0xe7, 0x8c, 0x1f, 0xba, 0x72, 0x57, 0x04, 0x4d,
+	0xc8, 0x30, 0x4d, 0x8f, 0x98, 0x34, 0xa1, 0xdc, 0x95, 0x84, 0x0e, 0x23, 0x09, 0x5e, 0x29, 0xa1,
+	0xcf, 0x21, 0x1d, 0x78, 0x46, 0x9f, 0x14, 0x41, 0x4b, 0x55, 0xf3, 0xf5, 0xf2, 0xc6, 0x3a, 0x76,
+	0xa9, 0x86, 0x6e, 0x07, 0xde, 0xb4, 0x91, 0x5b, 0x2e, 0xca, 0x69, 0x46, 0x63, 0x6e, 0x88, 0xce,
+	0x40, 0xf6, 0xb8, 0x5e, 0x2f, 0x98, 0xba, 0xa4, 0x98, 0x67, 0x0d, 0xf9, 0xef, 0x66, 0xa0, 0xa9,
+	0x4b, 0x78, 0x0a, 0x21, 0x87, 0x32, 0x70, 0xde, 0x5b, 0x11, 0x48, 0x83, 0x8c, 0xe3, 0x99, 0x3d,
+	0x6b, 0x50, 0x94, 0xe9, 0x34, 0x72, 0x87, 0x6d, 0xcf, 0x6c, 0x3e, 0xc5, 0x69, 0xc7, 0x33, 0x9b,
+	0x03, 0xf5, 0x31, 0xc0, 0x2a, 0x20, 0xa4, 0x40, 0xea, 0x1b, 0x32, 0x0d, 0x47, 0x97, 0x1e, 0xd1,
+	0x3e, 0xa4, 0xbf, 0x33, 0x46, 0x13, 0x3e, 0xab, 0x39, 0xcc, 0x89, 0x4f, 0xb7, 0x1e, 0x8b, 0x15,
+	0x0f, 0x24, 0xe6, 0xa3, 0x0e, 0x85, 0x4e, 0xb3, 0x75, 0x7c, 0xaa, 0xf7, 0xba, 0x7a, 0xeb, 0xb0,
+	0xd5, 0x55, 0x04, 0xb5, 0x3c, 0x9b, 0x6b, 0x77, 0x12, 0xa1, 0x52, 0xbd, 0x8e, 0x65, 0x9b, 0x23,
+	0xd2, 0x25, 0xb6, 0x61, 0xd3, 0xd2, 0xca, 0x67, 0x17, 0xa7, 0xdd, 0x66, 0x64, 0x22, 0xaa, 0xa5,
+	0xd9, 0x5c, 0x53, 0xaf, 0x98, 0x9c, 0x4d, 0x46, 0x81, 0xc5, 0x2d, 0x54, 0xe9, 0x87, 0x5f, 0x4a,
+	0x42, 0xa5, 0x03, 0x69, 0x36, 0x7b, 0xe8, 0x2e, 0x64, 0x8f, 0x71, 0xfb, 0xe2, 0xbc, 0xd7, 0xf8,
+	0x52, 0x11, 0xd4, 0xdd, 0xd9, 0x5c, 0xcb, 0xc7, 0x43, 0xd9, 0x98, 0xa2, 0xff, 0x83, 0xcc, 0xc5,
+	0xfa, 0x8b, 0x23, 0xfd, 0x9c, 0xe2, 0xdf, 0x9a, 0xcd, 0xb5, 0xdd, 0x58, 0x45, 0x7f, 0xd5, 0x27,
+	0x6e, 0x04, 0xfa, 0xbb, 0x08, 0xb9, 0xb8, 0x9d, 0xe8, 0x43, 0x90, 0x58, 0xe5, 0x45, 0x56, 0x79,
+	0xed, 0x7a, 0xc3, 0x57, 0x27, 0x56, 0x6f, 0xa6, 0x5d, 0x79, 0x05, 0x85, 0x35, 0x36, 0x2a, 0x83,
+	0xd4, 0x6a, 0xb7, 0x74, 0x45, 0x50, 0x6f, 0xcf, 0xe6, 0xda, 0xde, 0x9a, 0xb0, 0xe5, 0xd8, 0x04,
+	0xdd, 0x85, 0x54, 0xe7, 0xe2, 0x4c, 0x11, 0xd5, 0xfd, 0xd9, 0x5c, 0x53, 0xd6, 0xe4, 0x9d, 0xc9,
+	0x18, 0xdd, 0x83, 0xf4, 0x51, 0xfb, 0xa2, 0xd5, 0x55, 0xb6, 0xd4, 0x83, 0xd9, 0x5c, 0x43, 0x6b,
+	0x0a, 0x47, 0xce, 0x24, 0x2e, 0xc9, 0x03, 0x48, 0x75, 0x0d, 0x33, 0xd9, 0x39, 0x79, 0x43, 0xe7,
+	0xe4, 0xb0, 0x73, 0x95, 0x9f, 0xf3, 0x20, 0xf3, 0x32, 0xfb, 0xae, 0x63, 0xfb, 0x04, 0x7d, 0x02,
+	0x99, 0xa1, 0x67, 0x8c, 0x89, 0x5f, 0x14, 0xd9, 0xd0, 0xde, 0xb9, 0x32, 0x6b, 0x5c, 0xad, 0xf6,
+	0x8c, 0xea, 0x34, 0x24, 0x7a, 0x23, 0xe1, 0xd0, 0x40, 0x7d, 0x2d, 0x41, 0x9a, 0xf1, 0xd1, 0x13,
+	0xc8, 0xf0, 0x75, 0x63, 0x01, 0xe4, 0xeb, 0xf7, 0x36, 0x83, 0xf0, 0x05, 0x65, 0x26, 0x27, 0x02,
+	0x0e, 0x4d, 0xd0, 0x57, 0x20, 0x0f, 0x47, 0x8e, 0x11, 0xf4, 0xf8, 0xf2, 0x85, 0xb7, 0xe2, 0xfd,
+	0xb7, 0xc4, 0x41, 0x35, 0xf9, 0xca, 0xf2, 0x90, 0xd8, 0x02, 0x24, 0xb8, 0x27, 0x02, 0xce, 0x0f,
+	0x57, 0x24, 0x1a, 0xc0, 0x8e, 0x65, 0x07, 0xc4, 0x24, 0x5e, 0x84, 0x9f, 0x62, 0xf8, 0xd5, 0xcd,
+	0xf8, 0x4d, 0xae, 0x9b, 0xf4, 0xb0, 0xb7, 0x5c, 0x94, 0x0b, 0x6b, 0xfc, 0x13, 0x01, 0x17, 0xac,
+	0x24, 0x03, 0xbd, 0x84, 0xdd, 0x89, 0xed, 0x5b, 0xa6, 0x4d, 0x06, 0x91, 0x1b, 0x89, 0xb9, 0x79,
+	0x7f, 0xb3, 0x9b, 0x8b, 0x50, 0x39, 0xe9, 0x07, 0xd1, 0xab, 0x7e, 0x5d, 0x70, 0x22, 0xe0, 0x9d,
+	0xc9, 0x1a, 0x87, 0xe6, 0x73, 0xe9, 0x38, 0x23, 0x62, 0xd8, 0x91, 0xa3, 0xf4, 0xbb, 0xf2, 0x69,
+	0x70, 0xdd, 0x6b, 0xf9, 0xac, 0xf1, 0x69, 0x3e, 0x97, 0x49, 0x06, 0xfa, 0x9a, 0xbe, 0xc1, 0x9e,
+	0x65, 0x9b, 0x91, 0x93, 0x0c, 0x73, 0xf2, 0xde, 0x5b, 0xfa, 0xca, 0x54, 0x93, 0x3e, 0xf8, 0x7d,
+	0x9c, 0x60, 0x9f, 0x08, 0x58, 0xf6, 0x13, 0x74, 0x23, 0x03, 0x12, 0x7d, 0x1a, 0x55, 0x0f, 0xf2,
+	0x89, 0xb1, 0x40, 0xf7, 0x41, 0x0a, 0x0c, 0x33, 0x1a, 0x46, 0x79, 0xf5, 0x34, 0x1a, 0x66, 0x38,
+	0x7d, 0x4c, 0x8e, 0x9e, 0x40, 0x8e, 0x9a, 0xf3, 0x5b, 0x72, 0x8b, 0xed, 0x6a, 0x69, 0x73, 0x70,
+	0x4f, 0x8d, 0xc0, 0x60, 0x9b, 0x9a, 0x1d, 0x84, 0x27, 0xf5, 0x0b, 0x50, 0xae, 0xce, 0x11, 0x7d,
+	0x44, 0xe3, 0x67, 0x95, 0xbb, 0x57, 0x70, 0x82, 0x83, 0x0e, 0x20, 0xc3, 0x36, 0x88, 0xce, 0x67,
+	0xaa, 0x2a, 0xe2, 0x90, 0x52, 0x4f, 0x01, 0x5d, 0x9f, 0x99, 0x1b, 0xa2, 0xa5, 0x62, 0xb4, 0x33,
+	0xb8, 0xb5, 0x61, 0x34, 0x6e, 0x08, 0x27, 0x25, 0x83, 0xbb, 0x3e, 0x00, 0x37, 0x44, 0xcb, 0xc6,
+	0x68, 0xcf, 0x61, 0xef, 0x5a, 0xa7, 0x6f, 0x08, 0x96, 0x8b, 0xc0, 0x2a, 0x1d, 0xc8, 0x31, 0x80,
+	0xf0, 0xb6, 0xcc, 0x74, 0x74, 0xdc, 0xd4, 0x3b, 0x8a, 0xc0, 0x6f, 0xea, 0x58, 0xc4, 0x67, 0x83,
+	0x2a, 0x9c, 0xb7, 0x9b, 0xad, 0x6e, 0x47, 0x11, 0xaf, 0x28, 0xf0, 0x58, 0xc2, 0xcb, 0xf0, 0x37,
+	0x11, 0xb2, 0x51, 0xbf, 0xd1, 0xff, 0x20, 0xfd, 0xec, 0xb4, 0x7d, 0x48, 0x1f, 0xa4, 0xbd, 0xd9,
+	0x5c, 0x2b, 0x44, 0x02, 0xd6, 0x7a, 0xa4, 0xc1, 0x76, 0xb3, 0xd5, 0xd5, 0x8f, 0x75, 0x1c, 0x41,
+	0x46, 0xf2, 0xb0, 0x9d, 0xa8, 0x02, 0xd9, 0x8b, 0x56, 0xa7, 0x79, 0xdc, 0xd2, 0x9f, 0x2a, 0x5b,
+	0xfc, 0x9a, 0x8e, 0x54, 0xa2, 0x1e, 0x51, 0x94, 0x46, 0xbb, 0x7d, 0xaa, 0x1f, 0xb6, 0x94, 0xd4,
+	0x3a, 0x4a, 0x58, 0x77, 0x54, 0x82, 0x4c, 0xa7, 0x8b, 0x9b, 0xad, 0x63, 0x45, 0x52, 0xd1, 0x6c,
+	0xae, 0xed, 0x44, 0x0a, 0xbc, 0x94, 0x61, 0xe0, 0x3f, 0x8a, 0xb0, 0x7f, 0x64, 0xb8, 0xc6, 0xa5,
+	0x35, 0xb2, 0x02, 0x8b, 0xf8, 0xf1, 0xf5, 0xfc, 0x04, 0xa4, 0xbe, 0xe1, 0x46, 0xfb, 0xb0, 0xda,
+	0xbf, 0x4d, 0xca, 0x94, 0xe9, 0xb3, 0x87, 0x1c, 0x33, 0x23, 0xf5, 0x63, 0xc8, 0xc5, 0xac, 0x1b,
+	0xbd, 0xed, 0xbb, 0x50, 0x38, 0xa1, 0x65, 0x8d, 0x90, 0x2b, 0x8f, 0xe1, 0xca, 0x4f, 0x28, 0x35,
+	0xf6, 0x03, 0xc3, 0x0b, 0x18, 0x60, 0x0a, 0x73, 0x82, 0x3a, 0x21, 0xf6, 0x80, 0x01, 0xa6, 0x30,
+	0x3d, 0xd6, 0xff, 0x14, 0x61, 0xbb, 0xc3, 0x83, 0xa6, 0xc9, 0xd0, 0xd5, 0x44, 0xfb, 0x9b, 0xfe,
+	0x67, 0xd4, 0xdb, 0x1b, 0xf7, 0xb7, 0x22, 0x7d, 0xff, 0x6b, 0x51, 0x78, 0x24, 0xa2, 0xe7, 0x20,
+	0x27, 0x93, 0x46, 0x07, 0x35, 0xfe, 0x7b, 0x5f, 0x8b, 0x7e, 0xef, 0x6b, 0x3a, 0xfd, 0xbd, 0x57,
+	0xef, 0xbe, 0xb3, 0x46, 0x0c, 0x4e, 0x44, 0x9f, 0x41, 0x9a, 0x25, 0xf8, 0x56, 0x94, 0x83, 0x18,
+	0x65, 0xbd, 0x10, 0xd4, 0x7c, 0x4b, 0x65, 0x31, 0x35, 0xf6, 0xdf, 0xfc, 0x5d, 0x12, 0xde, 0x2c,
+	0x4b, 0xe2, 0x1f, 0xcb, 0x92, 0xf8, 0xd7, 0xb2, 0x24, 0xfe, 0xf4, 0x4f, 0x49, 0xb8, 0xcc, 0x30,
+	0xa4, 0x0f, 0xfe, 0x1d, 0x00, 0xc3, 0x5b, 0x4f, 0xe2, 0xc5, 0x0c, 0x00, 0x00,
 }
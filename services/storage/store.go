@@ -3,20 +3,70 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/estimator"
+	"github.com/influxdata/influxdb/pkg/limiter"
+	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/services/meta"
 	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxql"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
+var (
+	// ErrTooManyReads is returned by the read methods of Store when
+	// RejectExcessReads is set and MaxConcurrentReads requests are already
+	// accessing shards.
+	ErrTooManyReads = errors.New("too many concurrent reads")
+
+	// ErrDatabaseNotFound is returned when the database of a request does
+	// not exist.
+	ErrDatabaseNotFound = errors.New("database not found")
+
+	// ErrRetentionPolicyNotFound is returned when the retention policy of a
+	// request does not exist in its database.
+	ErrRetentionPolicyNotFound = errors.New("retention policy not found")
+)
+
 type Store struct {
 	TSDBStore  *tsdb.Store
 	MetaClient StorageMetaClient
 	Logger     *zap.Logger
+
+	// MaxConcurrentReads limits the number of reads which may access shards
+	// simultaneously. It applies to Read, ReadFieldKeys, ReadMeasurementNames
+	// and SeriesCardinality. Read holds its slot until the ResultSet is
+	// closed, the others until they return. Zero means no limit.
+	MaxConcurrentReads int
+
+	// RejectExcessReads causes reads to return ErrTooManyReads rather than
+	// block when MaxConcurrentReads has been reached.
+	RejectExcessReads bool
+
+	// RequireFullCoverage causes Read to return an error if the shard groups
+	// selected for a request do not cover its entire time range. Only requests
+	// with both a start and an end time are checked, as a range with an open
+	// bound can never be covered.
+	RequireFullCoverage bool
+
+	// RejectUnknownGroupKeys causes Read to return an error if a group key is
+	// not a tag key of any series matching the request's predicate. The check
+	// reads the tag keys of every selected shard, so it is disabled by
+	// default, in which case a key with no series is an empty group.
+	RejectUnknownGroupKeys bool
+
+	readLimiterOnce sync.Once
+	readLimiter     limiter.Fixed
+
+	metrics *readMetrics
 }
 
 func NewStore() *Store {
@@ -28,21 +78,81 @@ func (s *Store) WithLogger(log *zap.Logger) {
 	s.Logger = log.With(zap.String("service", "store"))
 }
 
-func (s *Store) Read(ctx context.Context, req *ReadRequest) (*ResultSet, error) {
-	database, rp := req.Database, ""
+// WithMetrics enables the collection of metrics for the read methods of the
+// store and registers them with reg. If the metrics are already registered
+// with reg, for example by another store, the store records to those. If reg
+// is nil, the collectors returned by PrometheusCollectors must be registered
+// by the caller.
+func (s *Store) WithMetrics(reg prometheus.Registerer) {
+	s.metrics = newReadMetrics()
+	if reg != nil {
+		s.metrics.register(reg)
+	}
+}
+
+// PrometheusCollectors returns the collectors for the metrics of the store, or
+// nil if WithMetrics has not been called.
+func (s *Store) PrometheusCollectors() []prometheus.Collector {
+	return s.metrics.PrometheusCollectors()
+}
 
-	if req.RequestType == ReadRequestTypeMultiTenant {
-		// TODO(sgc): this should be moved to configuration
-		database, rp = "db", "rp"
-	} else {
-		if p := strings.IndexByte(database, '/'); p > -1 {
-			database, rp = database[:p], database[p+1:]
+// acquireRead takes a slot from the read limiter, blocking until one is
+// available unless RejectExcessReads is set. The returned function must be
+// called to release the slot.
+func (s *Store) acquireRead(ctx context.Context) (func(), error) {
+	if s.MaxConcurrentReads <= 0 {
+		return func() {}, nil
+	}
+
+	s.readLimiterOnce.Do(func() {
+		s.readLimiter = limiter.NewFixed(s.MaxConcurrentReads)
+	})
+
+	if s.RejectExcessReads {
+		if !s.readLimiter.TryTake() {
+			return nil, ErrTooManyReads
 		}
+		return s.readLimiter.Release, nil
+	}
+
+	select {
+	case s.readLimiter <- struct{}{}:
+		return s.readLimiter.Release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startSpan starts a span named operationName as a child of the span in ctx
+// and returns it with a context containing it. If ctx has no span, tracing is
+// not enabled and a no-op span is returned with ctx.
+func startSpan(ctx context.Context, operationName string) (opentracing.Span, context.Context) {
+	parent := opentracing.SpanFromContext(ctx)
+	if parent == nil {
+		return noopSpan, ctx
+	}
+
+	span := parent.Tracer().StartSpan(operationName, opentracing.ChildOf(parent.Context()))
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+var noopSpan = opentracing.NoopTracer{}.StartSpan("")
+
+// validateArgs resolves the database and retention policy from a database
+// argument of the form db[/rp] and applies the default time range. It returns
+// ErrDatabaseNotFound or ErrRetentionPolicyNotFound if either does not exist.
+func (s *Store) validateArgs(ctx context.Context, database string, start, end int64) (string, string, int64, int64, error) {
+	span, _ := startSpan(ctx, "storage.validateArgs")
+	defer span.Finish()
+
+	rp := ""
+	if p := strings.IndexByte(database, '/'); p > -1 {
+		database, rp = database[:p], database[p+1:]
 	}
 
 	di := s.MetaClient.Database(database)
 	if di == nil {
-		return nil, errors.New("no database")
+		return "", "", 0, 0, ErrDatabaseNotFound
 	}
 
 	if rp == "" {
@@ -51,56 +161,173 @@ func (s *Store) Read(ctx context.Context, req *ReadRequest) (*ResultSet, error)
 
 	rpi := di.RetentionPolicy(rp)
 	if rpi == nil {
-		return nil, errors.New("invalid retention policy")
+		return "", "", 0, 0, ErrRetentionPolicyNotFound
 	}
 
-	var start, end = models.MinNanoTime, models.MaxNanoTime
-	if req.TimestampRange.Start > 0 {
-		start = req.TimestampRange.Start
+	if start <= 0 {
+		start = models.MinNanoTime
 	}
 
-	if req.TimestampRange.End > 0 {
-		end = req.TimestampRange.End
+	if end <= 0 {
+		end = models.MaxNanoTime
 	}
 
-	groups, err := s.MetaClient.ShardGroupsByTimeRange(database, rp, time.Unix(0, start), time.Unix(0, end))
+	return database, rp, start, end, nil
+}
+
+// findShardIDs returns the IDs of the shards which overlap the time range
+// [start, end], ordered by the time of their shard group. If full is true, it
+// returns an error if the shard groups do not cover the entire time range.
+func (s *Store) findShardIDs(ctx context.Context, database, rp string, desc, full bool, start, end int64) ([]uint64, error) {
+	span, _ := startSpan(ctx, "storage.findShardIDs")
+	defer span.Finish()
+
+	groups, err := s.findShardGroups(database, rp, desc, start, end)
 	if err != nil {
 		return nil, err
 	}
 
+	if full {
+		if err := checkCoverage(groups, start, end); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(groups) == 0 {
 		return nil, nil
 	}
 
-	if req.Descending {
+	shardIDs := make([]uint64, 0, len(groups[0].Shards)*len(groups))
+	for _, g := range groups {
+		for _, si := range g.Shards {
+			shardIDs = append(shardIDs, si.ID)
+		}
+	}
+
+	return shardIDs, nil
+}
+
+// findShardGroups returns the shard groups which overlap the time range
+// [start, end], ordered by time.
+func (s *Store) findShardGroups(database, rp string, desc bool, start, end int64) ([]meta.ShardGroupInfo, error) {
+	groups, err := s.MetaClient.ShardGroupsByTimeRange(database, rp, time.Unix(0, start), time.Unix(0, end))
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+
+	if desc {
 		sort.Sort(sort.Reverse(meta.ShardGroupInfos(groups)))
 	} else {
 		sort.Sort(meta.ShardGroupInfos(groups))
 	}
 
-	shardIDs := make([]uint64, 0, len(groups[0].Shards)*len(groups))
-	for _, g := range groups {
-		for _, si := range g.Shards {
-			shardIDs = append(shardIDs, si.ID)
+	return groups, nil
+}
+
+// validateGroupKeys returns an error listing the keys which are not the tag
+// keys of any series in the shards matching the tag comparisons of p.
+func (s *Store) validateGroupKeys(shardIDs []uint64, keys []string, p *Predicate) error {
+	cond, err := measurementCondition(p)
+	if err != nil {
+		return err
+	}
+
+	tagKeys, err := s.TSDBStore.TagKeys(query.OpenAuthorizer, shardIDs, cond)
+	if err != nil {
+		return err
+	}
+
+	known := map[string]struct{}{
+		string(measurementKey): {},
+		string(fieldKey):       {},
+	}
+	for _, tk := range tagKeys {
+		for _, k := range tk.Keys {
+			known[k] = struct{}{}
+		}
+	}
+
+	if unknown := unknownKeys(known, keys); len(unknown) > 0 {
+		return fmt.Errorf("unknown group keys: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// unknownKeys returns the elements of keys which are not in known.
+func unknownKeys(known map[string]struct{}, keys []string) []string {
+	var unknown []string
+	for _, k := range keys {
+		if _, ok := known[k]; !ok {
+			unknown = append(unknown, k)
+		}
+	}
+	return unknown
+}
+
+// readDatabase returns the database argument, of the form db[/rp], for req.
+func readDatabase(req *ReadRequest) string {
+	if req.RequestType == ReadRequestTypeMultiTenant {
+		// TODO(sgc): this should be moved to configuration
+		return "db/rp"
+	}
+	return req.Database
+}
+
+func (s *Store) Read(ctx context.Context, req *ReadRequest) (rs *ResultSet, err error) {
+	defer func(start time.Time) { s.metrics.observe(methodRead, start, err) }(time.Now())
+
+	span, ctx := startSpan(ctx, "storage.Read")
+	defer func() {
+		// a ResultSet finishes the span when it is closed
+		if rs == nil {
+			span.Finish()
+		}
+	}()
+
+	database, rp, start, end, err := s.validateArgs(ctx, readDatabase(req), req.TimestampRange.Start, req.TimestampRange.End)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("start", start).SetTag("end", end)
+
+	full := s.RequireFullCoverage && start != models.MinNanoTime && end != models.MaxNanoTime
+	shardIDs, err := s.findShardIDs(ctx, database, rp, req.Descending, full, start, end)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.setShards(methodRead, len(shardIDs))
+	span.SetTag("shard_count", len(shardIDs))
+
+	if len(shardIDs) == 0 {
+		return nil, nil
+	}
+
+	release, err := s.acquireRead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// validating the group keys reads the index of every shard, so it is
+	// subject to the read limit
+	if s.RejectUnknownGroupKeys && len(req.Grouping) > 0 {
+		if err := s.validateGroupKeys(shardIDs, req.Grouping, req.Predicate); err != nil {
+			release()
+			return nil, err
 		}
 	}
 
 	var cur seriesCursor
 	if ic, err := newIndexSeriesCursor(ctx, req, s.TSDBStore.Shards(shardIDs)); err != nil {
+		release()
 		return nil, err
 	} else if ic == nil {
+		release()
 		return nil, nil
 	} else {
 		cur = ic
 	}
 
-	if len(req.Grouping) > 0 {
-		cur = newGroupSeriesCursor(ctx, cur, req.Grouping)
-	}
-
-	if req.SeriesLimit > 0 || req.SeriesOffset > 0 {
-		cur = newLimitSeriesCursor(ctx, cur, req.SeriesLimit, req.SeriesOffset)
-	}
+	cur = newLimitGroupSeriesCursor(ctx, cur, req)
 
 	return &ResultSet{
 		req: readRequest{
@@ -111,6 +338,598 @@ func (s *Store) Read(ctx context.Context, req *ReadRequest) (*ResultSet, error)
 			limit:     req.PointsLimit,
 			aggregate: req.Aggregate,
 		},
-		cur: cur,
+		cur:     cur,
+		release: release,
+		span:    span,
 	}, nil
 }
+
+// checkCoverage returns an error describing the first gap in the time range
+// [start, end] which is not covered by one of groups.
+func checkCoverage(groups []meta.ShardGroupInfo, start, end int64) error {
+	groups = append([]meta.ShardGroupInfo(nil), groups...)
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].StartTime.Before(groups[j].StartTime)
+	})
+
+	// next is the earliest time not yet covered
+	next := start
+	for i := range groups {
+		gs, ge := groups[i].StartTime.UnixNano(), groups[i].EndTime.UnixNano()
+		if gs > next {
+			return fmt.Errorf("shard groups do not cover time range: gap from %s to %s", formatNanoTime(next), formatNanoTime(gs))
+		}
+		if ge > next {
+			next = ge
+		}
+		if next > end {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("shard groups do not cover time range: gap from %s to %s", formatNanoTime(next), formatNanoTime(end))
+}
+
+func formatNanoTime(t int64) string {
+	return time.Unix(0, t).UTC().Format(time.RFC3339Nano)
+}
+
+// ReadFieldKeysRequest describes the field keys returned by ReadFieldKeys.
+type ReadFieldKeysRequest struct {
+	Database       string
+	TimestampRange TimestampRange
+
+	// Predicate optionally restricts the measurements whose fields are
+	// returned to those with a series matching the tag comparisons.
+	// Comparisons of field keys and values are ignored.
+	Predicate *Predicate
+}
+
+// FieldKey is a field key and its data type.
+type FieldKey struct {
+	Key  string
+	Type influxql.DataType
+}
+
+// ReadFieldKeys returns the sorted field keys and their types for the
+// measurements in the shards which overlap the requested time range.
+func (s *Store) ReadFieldKeys(ctx context.Context, req *ReadFieldKeysRequest) (_ []FieldKey, err error) {
+	defer func(start time.Time) { s.metrics.observe(methodReadFieldKeys, start, err) }(time.Now())
+
+	database, rp, start, end, err := s.validateArgs(ctx, req.Database, req.TimestampRange.Start, req.TimestampRange.End)
+	if err != nil {
+		return nil, err
+	}
+
+	shardIDs, err := s.findShardIDs(ctx, database, rp, false, false, start, end)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.setShards(methodReadFieldKeys, len(shardIDs))
+
+	release, err := s.acquireRead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	cond, err := measurementCondition(req.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []FieldKey
+	for _, sh := range s.TSDBStore.Shards(shardIDs) {
+		names, err := shardMeasurementNames(ctx, sh, cond)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			mf := sh.MeasurementFields(name)
+			if mf == nil {
+				continue
+			}
+
+			mf.ForEachField(func(key string, typ influxql.DataType) bool {
+				keys = append(keys, FieldKey{Key: key, Type: typ})
+				return true
+			})
+		}
+	}
+
+	return MergeFieldKeys(keys), nil
+}
+
+// MergeFieldKeys sorts keys by key and type and removes duplicates. A key
+// which has different types in different measurements or shards is returned
+// once per type.
+func MergeFieldKeys(keys []FieldKey) []FieldKey {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Key != keys[j].Key {
+			return keys[i].Key < keys[j].Key
+		}
+		return keys[i].Type < keys[j].Type
+	})
+
+	merged := keys[:1]
+	for _, k := range keys[1:] {
+		if k != merged[len(merged)-1] {
+			merged = append(merged, k)
+		}
+	}
+	return merged
+}
+
+// measurementCondition converts the tag comparisons of p to an expression
+// which can be evaluated against the series in the index. It returns nil if p
+// is empty or only compares field keys and values.
+func measurementCondition(p *Predicate) (influxql.Expr, error) {
+	root := p.GetRoot()
+	if root == nil {
+		return nil, nil
+	}
+
+	cond, err := NodeToExpr(root, measurementRemap)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasField, hasValue := HasFieldKeyOrValue(cond); hasField || hasValue {
+		cond = influxql.Reduce(RewriteExprRemoveFieldKeyAndValue(cond), nil)
+		if isBooleanLiteral(cond) {
+			return nil, nil
+		}
+	}
+
+	return cond, nil
+}
+
+// shardMeasurementNames returns the names of the measurements in sh which have
+// a series matching cond, or all measurements if cond is nil.
+func shardMeasurementNames(ctx context.Context, sh *tsdb.Shard, cond influxql.Expr) ([][]byte, error) {
+	var names [][]byte
+	if cond == nil {
+		err := sh.ForEachMeasurementName(func(name []byte) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		})
+		return names, err
+	}
+
+	cur, err := tsdb.Shards{sh}.CreateSeriesCursor(ctx, tsdb.SeriesCursorRequest{}, cond)
+	if err != nil || cur == nil {
+		return nil, err
+	}
+	defer cur.Close()
+
+	seen := make(map[string]struct{})
+	for {
+		row, err := cur.Next()
+		if err != nil {
+			return nil, err
+		} else if row == nil {
+			return names, nil
+		}
+
+		if _, ok := seen[string(row.Name)]; !ok {
+			seen[string(row.Name)] = struct{}{}
+			names = append(names, append([]byte(nil), row.Name...))
+		}
+	}
+}
+
+// ReadMeasurementNamesRequest describes the measurements returned by
+// ReadMeasurementNames.
+type ReadMeasurementNamesRequest struct {
+	Database       string
+	TimestampRange TimestampRange
+
+	// Predicate optionally restricts the measurements to those with a series
+	// matching the tag comparisons. Comparisons of field keys and values are
+	// ignored.
+	Predicate *Predicate
+}
+
+// ReadMeasurementNames returns the sorted names of the measurements in the
+// shards which overlap the requested time range.
+func (s *Store) ReadMeasurementNames(ctx context.Context, req *ReadMeasurementNamesRequest) (_ []string, err error) {
+	defer func(start time.Time) { s.metrics.observe(methodReadMeasurementNames, start, err) }(time.Now())
+
+	database, rp, start, end, err := s.validateArgs(ctx, req.Database, req.TimestampRange.Start, req.TimestampRange.End)
+	if err != nil {
+		return nil, err
+	}
+
+	shardIDs, err := s.findShardIDs(ctx, database, rp, false, false, start, end)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.setShards(methodReadMeasurementNames, len(shardIDs))
+
+	release, err := s.acquireRead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	cond, err := measurementCondition(req.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, sh := range s.TSDBStore.Shards(shardIDs) {
+		shardNames, err := shardMeasurementNames(ctx, sh, cond)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range shardNames {
+			names = append(names, string(name))
+		}
+	}
+
+	return mergeStrings(names), nil
+}
+
+// mergeStrings sorts a and removes duplicates.
+func mergeStrings(a []string) []string {
+	if len(a) == 0 {
+		return nil
+	}
+
+	sort.Strings(a)
+
+	merged := a[:1]
+	for _, v := range a[1:] {
+		if v != merged[len(merged)-1] {
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
+
+// CardinalityRequest describes the series counted by SeriesCardinality.
+type CardinalityRequest struct {
+	Database       string
+	TimestampRange TimestampRange
+
+	// Predicate optionally restricts the series counted to those matching the
+	// tag comparisons. Comparisons of field keys and values are ignored.
+	// Series are counted exactly when a predicate is specified.
+	Predicate *Predicate
+
+	// Exact counts every series rather than estimating the cardinality from
+	// the shards' sketches.
+	Exact bool
+}
+
+// SeriesCardinality returns the number of series in the shards which overlap
+// the requested time range. Unless Exact is set or a predicate is specified,
+// the result is an estimate obtained by merging the series sketches of each
+// shard.
+func (s *Store) SeriesCardinality(ctx context.Context, req *CardinalityRequest) (_ uint64, err error) {
+	defer func(start time.Time) { s.metrics.observe(methodSeriesCardinality, start, err) }(time.Now())
+
+	database, rp, start, end, err := s.validateArgs(ctx, req.Database, req.TimestampRange.Start, req.TimestampRange.End)
+	if err != nil {
+		return 0, err
+	}
+
+	shardIDs, err := s.findShardIDs(ctx, database, rp, false, false, start, end)
+	if err != nil {
+		return 0, err
+	}
+	s.metrics.setShards(methodSeriesCardinality, len(shardIDs))
+
+	release, err := s.acquireRead(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	shards := s.TSDBStore.Shards(shardIDs)
+	if len(shards) == 0 {
+		return 0, nil
+	}
+
+	cond, err := measurementCondition(req.Predicate)
+	if err != nil {
+		return 0, err
+	}
+
+	if cond != nil || req.Exact {
+		return countSeries(ctx, shards, cond)
+	}
+
+	sketches := make([]estimator.Sketch, 0, len(shards))
+	tombstones := make([]estimator.Sketch, 0, len(shards))
+	for _, sh := range shards {
+		ss, ts, err := sh.SeriesSketches()
+		if err != nil {
+			return 0, err
+		}
+		sketches = append(sketches, ss)
+		tombstones = append(tombstones, ts)
+	}
+
+	return estimateCardinality(sketches, tombstones)
+}
+
+// countSeries returns the exact number of distinct series in shards matching
+// cond.
+func countSeries(ctx context.Context, shards []*tsdb.Shard, cond influxql.Expr) (uint64, error) {
+	cur, err := tsdb.Shards(shards).CreateSeriesCursor(ctx, tsdb.SeriesCursorRequest{}, cond)
+	if err != nil || cur == nil {
+		return 0, err
+	}
+	defer cur.Close()
+
+	var n uint64
+	for {
+		row, err := cur.Next()
+		if err != nil {
+			return 0, err
+		} else if row == nil {
+			return n, nil
+		}
+		n++
+	}
+}
+
+// estimateCardinality merges the series and tombstone sketches of several
+// shards and returns the estimated number of live series. The sketches are not
+// modified.
+func estimateCardinality(sketches, tombstones []estimator.Sketch) (uint64, error) {
+	ss, err := mergeSketches(sketches)
+	if err != nil || ss == nil {
+		return 0, err
+	}
+
+	ts, err := mergeSketches(tombstones)
+	if err != nil {
+		return 0, err
+	}
+
+	n := ss.Count()
+	if ts != nil {
+		if t := ts.Count(); t < n {
+			n -= t
+		} else {
+			n = 0
+		}
+	}
+	return n, nil
+}
+
+func mergeSketches(sketches []estimator.Sketch) (estimator.Sketch, error) {
+	var merged estimator.Sketch
+	for _, sk := range sketches {
+		if sk == nil {
+			continue
+		}
+
+		if merged == nil {
+			merged = sk.Clone()
+		} else if err := merged.Merge(sk); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// TagKeysRequest describes the tag keys read by ReadTagKeys,
+// TagKeySeriesCounts and TagKeyFirstSeen.
+type TagKeysRequest struct {
+	Database       string
+	TimestampRange TimestampRange
+
+	// Predicate optionally restricts the series whose tag keys are read to
+	// those matching the tag comparisons. Comparisons of field keys and values
+	// are ignored.
+	Predicate *Predicate
+
+	// SortByRecency orders the keys returned by ReadTagKeys by the start time
+	// of the latest shard group with each key, newest first, rather than by
+	// key.
+	SortByRecency bool
+}
+
+// ReadTagKeys returns the tag keys of the series in the shards which overlap
+// the requested time range, sorted by key or, if SortByRecency is set, by
+// recency.
+func (s *Store) ReadTagKeys(ctx context.Context, req *TagKeysRequest) (_ []string, err error) {
+	defer func(start time.Time) { s.metrics.observe(methodReadTagKeys, start, err) }(time.Now())
+
+	groups, err := s.readGroupTagKeys(ctx, methodReadTagKeys, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SortByRecency {
+		return sortByRecency(groups), nil
+	}
+
+	var keys []string
+	for _, g := range groups {
+		keys = append(keys, g.keys...)
+	}
+	return mergeStrings(keys), nil
+}
+
+// TagKeySeriesCounts returns the number of series with each tag key in the
+// shards which overlap the requested time range. A series in more than one
+// shard is counted once.
+func (s *Store) TagKeySeriesCounts(ctx context.Context, req *TagKeysRequest) (_ map[string]uint64, err error) {
+	defer func(start time.Time) { s.metrics.observe(methodTagKeySeriesCounts, start, err) }(time.Now())
+
+	database, rp, start, end, err := s.validateArgs(ctx, req.Database, req.TimestampRange.Start, req.TimestampRange.End)
+	if err != nil {
+		return nil, err
+	}
+
+	shardIDs, err := s.findShardIDs(ctx, database, rp, false, false, start, end)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.setShards(methodTagKeySeriesCounts, len(shardIDs))
+
+	release, err := s.acquireRead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	shards := s.TSDBStore.Shards(shardIDs)
+	if len(shards) == 0 {
+		return nil, nil
+	}
+
+	cond, err := measurementCondition(req.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cursor merges the series of every shard, so each is read once
+	cur, err := tsdb.Shards(shards).CreateSeriesCursor(ctx, tsdb.SeriesCursorRequest{}, cond)
+	if err != nil || cur == nil {
+		return nil, err
+	}
+	defer cur.Close()
+
+	return countTagKeys(cur)
+}
+
+// countTagKeys returns the number of series read from cur with each tag key.
+func countTagKeys(cur tsdb.SeriesCursor) (map[string]uint64, error) {
+	counts := make(map[string]uint64)
+	for {
+		row, err := cur.Next()
+		if err != nil {
+			return nil, err
+		} else if row == nil {
+			return counts, nil
+		}
+
+		for _, t := range row.Tags {
+			counts[string(t.Key)]++
+		}
+	}
+}
+
+// TagKeyFirstSeen returns the start time of the earliest shard group with a
+// series with each tag key, of those which overlap the requested time range.
+func (s *Store) TagKeyFirstSeen(ctx context.Context, req *TagKeysRequest) (_ map[string]int64, err error) {
+	defer func(start time.Time) { s.metrics.observe(methodTagKeyFirstSeen, start, err) }(time.Now())
+
+	groups, err := s.readGroupTagKeys(ctx, methodTagKeyFirstSeen, req)
+	if err != nil {
+		return nil, err
+	}
+	return firstSeen(groups), nil
+}
+
+// groupTagKeys holds the tag keys of the series in a shard group.
+type groupTagKeys struct {
+	start int64 // start time of the shard group
+	keys  []string
+}
+
+// readGroupTagKeys returns the tag keys of the series matching req in each
+// shard group which overlaps the requested time range, ordered by time.
+func (s *Store) readGroupTagKeys(ctx context.Context, method string, req *TagKeysRequest) ([]groupTagKeys, error) {
+	database, rp, start, end, err := s.validateArgs(ctx, req.Database, req.TimestampRange.Start, req.TimestampRange.End)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.findShardGroups(database, rp, false, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var n int
+	for _, g := range groups {
+		n += len(g.Shards)
+	}
+	s.metrics.setShards(method, n)
+
+	release, err := s.acquireRead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	cond, err := measurementCondition(req.Predicate)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]groupTagKeys, 0, len(groups))
+	for _, g := range groups {
+		shardIDs := make([]uint64, 0, len(g.Shards))
+		for _, si := range g.Shards {
+			shardIDs = append(shardIDs, si.ID)
+		}
+
+		tagKeys, err := s.TSDBStore.TagKeys(query.OpenAuthorizer, shardIDs, cond)
+		if err != nil {
+			return nil, err
+		}
+
+		var keys []string
+		for _, tk := range tagKeys {
+			keys = append(keys, tk.Keys...)
+		}
+		result = append(result, groupTagKeys{start: g.StartTime.UnixNano(), keys: mergeStrings(keys)})
+	}
+
+	return result, nil
+}
+
+// firstSeen returns the earliest start time of the groups with each key.
+func firstSeen(groups []groupTagKeys) map[string]int64 {
+	first := make(map[string]int64)
+	for _, g := range groups {
+		for _, k := range g.keys {
+			if t, ok := first[k]; !ok || g.start < t {
+				first[k] = g.start
+			}
+		}
+	}
+	return first
+}
+
+// sortByRecency returns the keys of groups ordered by the latest start time of
+// the groups with each key, newest first. Keys last seen at the same time are
+// sorted by key.
+func sortByRecency(groups []groupTagKeys) []string {
+	last := make(map[string]int64)
+	for _, g := range groups {
+		for _, k := range g.keys {
+			if t, ok := last[k]; !ok || g.start > t {
+				last[k] = g.start
+			}
+		}
+	}
+	if len(last) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(last))
+	for k := range last {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ti, tj := last[keys[i]], last[keys[j]]; ti != tj {
+			return ti > tj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
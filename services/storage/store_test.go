@@ -0,0 +1,960 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/pkg/estimator"
+	"github.com/influxdata/influxdb/pkg/estimator/hll"
+	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/tsdb"
+	_ "github.com/influxdata/influxdb/tsdb/index"
+	"github.com/influxdata/influxql"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestStore_acquireRead(t *testing.T) {
+	const limit = 2
+	s := &Store{MaxConcurrentReads: limit}
+
+	var active, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := s.acquireRead(context.Background())
+			if err != nil {
+				t.Error("acquireRead", err)
+				return
+			}
+			defer release()
+
+			n := atomic.AddInt32(&active, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&peak); got > limit {
+		t.Errorf("unexpected concurrent reads: got %d, exp <= %d", got, limit)
+	}
+}
+
+func TestStore_acquireRead_Reject(t *testing.T) {
+	s := &Store{MaxConcurrentReads: 1, RejectExcessReads: true}
+
+	release, err := s.acquireRead(context.Background())
+	if err != nil {
+		t.Fatal("acquireRead", err)
+	}
+
+	if _, err := s.acquireRead(context.Background()); err != ErrTooManyReads {
+		t.Fatalf("unexpected error: got %v, exp %v", err, ErrTooManyReads)
+	}
+
+	release()
+
+	release, err = s.acquireRead(context.Background())
+	if err != nil {
+		t.Fatal("acquireRead after release", err)
+	}
+	release()
+}
+
+func TestStore_RejectExcessReads(t *testing.T) {
+	s := NewStore()
+	s.MetaClient = newMetaClient()
+	s.MaxConcurrentReads = 1
+	s.RejectExcessReads = true
+
+	release, err := s.acquireRead(context.Background())
+	if err != nil {
+		t.Fatal("acquireRead", err)
+	}
+	defer release()
+
+	ctx := context.Background()
+	cases := []struct {
+		n  string
+		fn func() error
+	}{
+		{n: "ReadFieldKeys", fn: func() error {
+			_, err := s.ReadFieldKeys(ctx, &ReadFieldKeysRequest{Database: "db0"})
+			return err
+		}},
+		{n: "ReadMeasurementNames", fn: func() error {
+			_, err := s.ReadMeasurementNames(ctx, &ReadMeasurementNamesRequest{Database: "db0"})
+			return err
+		}},
+		{n: "SeriesCardinality", fn: func() error {
+			_, err := s.SeriesCardinality(ctx, &CardinalityRequest{Database: "db0"})
+			return err
+		}},
+		{n: "TagKeySeriesCounts", fn: func() error {
+			_, err := s.TagKeySeriesCounts(ctx, &TagKeysRequest{Database: "db0"})
+			return err
+		}},
+		{n: "TagKeyFirstSeen", fn: func() error {
+			_, err := s.TagKeyFirstSeen(ctx, &TagKeysRequest{Database: "db0"})
+			return err
+		}},
+		{n: "ReadTagKeys", fn: func() error {
+			_, err := s.ReadTagKeys(ctx, &TagKeysRequest{Database: "db0"})
+			return err
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			if err := tc.fn(); err != ErrTooManyReads {
+				t.Errorf("unexpected error: got %v, exp %v", err, ErrTooManyReads)
+			}
+		})
+	}
+}
+
+func TestStore_acquireRead_Cancel(t *testing.T) {
+	s := &Store{MaxConcurrentReads: 1}
+
+	release, err := s.acquireRead(context.Background())
+	if err != nil {
+		t.Fatal("acquireRead", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.acquireRead(ctx); err != context.Canceled {
+		t.Fatalf("unexpected error: got %v, exp %v", err, context.Canceled)
+	}
+}
+
+func TestCheckCoverage(t *testing.T) {
+	group := func(start, end int64) meta.ShardGroupInfo {
+		return meta.ShardGroupInfo{StartTime: time.Unix(0, start), EndTime: time.Unix(0, end)}
+	}
+
+	cases := []struct {
+		n          string
+		groups     []meta.ShardGroupInfo
+		start, end int64
+		exp        string
+	}{
+		{
+			n:      "contiguous",
+			groups: []meta.ShardGroupInfo{group(0, 10), group(10, 20), group(20, 30)},
+			start:  5,
+			end:    25,
+		},
+		{
+			n:      "overlapping out of order",
+			groups: []meta.ShardGroupInfo{group(15, 30), group(0, 20)},
+			start:  0,
+			end:    29,
+		},
+		{
+			n:      "gap",
+			groups: []meta.ShardGroupInfo{group(0, 10), group(20, 30)},
+			start:  5,
+			end:    25,
+			exp:    "shard groups do not cover time range: gap from 1970-01-01T00:00:00.00000001Z to 1970-01-01T00:00:00.00000002Z",
+		},
+		{
+			n:      "gap at start",
+			groups: []meta.ShardGroupInfo{group(10, 20)},
+			start:  5,
+			end:    15,
+			exp:    "shard groups do not cover time range: gap from 1970-01-01T00:00:00.000000005Z to 1970-01-01T00:00:00.00000001Z",
+		},
+		{
+			n:      "end is inclusive",
+			groups: []meta.ShardGroupInfo{group(0, 10)},
+			start:  0,
+			end:    10,
+			exp:    "shard groups do not cover time range: gap from 1970-01-01T00:00:00.00000001Z to 1970-01-01T00:00:00.00000001Z",
+		},
+		{
+			n:     "no groups",
+			start: 0,
+			end:   10,
+			exp:   "shard groups do not cover time range: gap from 1970-01-01T00:00:00Z to 1970-01-01T00:00:00.00000001Z",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			var got string
+			if err := checkCoverage(tc.groups, tc.start, tc.end); err != nil {
+				got = err.Error()
+			}
+			if got != tc.exp {
+				t.Errorf("unexpected error: got %q, exp %q", got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestMergeFieldKeys(t *testing.T) {
+	cases := []struct {
+		n    string
+		keys []FieldKey
+		exp  []FieldKey
+	}{
+		{n: "empty"},
+		{
+			n: "sorted and deduplicated across shards",
+			keys: []FieldKey{
+				{Key: "usage_user", Type: influxql.Float},
+				{Key: "count", Type: influxql.Integer},
+				{Key: "usage_user", Type: influxql.Float},
+				{Key: "active", Type: influxql.Boolean},
+				{Key: "count", Type: influxql.Integer},
+			},
+			exp: []FieldKey{
+				{Key: "active", Type: influxql.Boolean},
+				{Key: "count", Type: influxql.Integer},
+				{Key: "usage_user", Type: influxql.Float},
+			},
+		},
+		{
+			n: "conflicting types",
+			keys: []FieldKey{
+				{Key: "value", Type: influxql.String},
+				{Key: "value", Type: influxql.Float},
+				{Key: "value", Type: influxql.Float},
+			},
+			exp: []FieldKey{
+				{Key: "value", Type: influxql.Float},
+				{Key: "value", Type: influxql.String},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got := MergeFieldKeys(tc.keys)
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected keys; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestMergeStrings(t *testing.T) {
+	cases := []struct {
+		n   string
+		a   []string
+		exp []string
+	}{
+		{n: "empty"},
+		{n: "single", a: []string{"cpu"}, exp: []string{"cpu"}},
+		{n: "overlapping shards", a: []string{"mem", "cpu", "disk", "cpu", "mem"}, exp: []string{"cpu", "disk", "mem"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got := mergeStrings(tc.a)
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected names; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	known := map[string]struct{}{"_measurement": {}, "_field": {}, "host": {}, "region": {}}
+
+	cases := []struct {
+		n    string
+		keys []string
+		exp  []string
+	}{
+		{n: "all known", keys: []string{"region", "host", "_measurement"}},
+		{n: "typo", keys: []string{"hots", "region"}, exp: []string{"hots"}},
+		{n: "several unknown", keys: []string{"dc", "host", "rack"}, exp: []string{"dc", "rack"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got := unknownKeys(known, tc.keys)
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected keys; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestEstimateCardinality(t *testing.T) {
+	sketch := func(keys ...string) estimator.Sketch {
+		s := hll.NewDefaultPlus()
+		for _, k := range keys {
+			s.Add([]byte(k))
+		}
+		return s
+	}
+
+	cases := []struct {
+		n          string
+		sketches   []estimator.Sketch
+		tombstones []estimator.Sketch
+		exp        uint64
+	}{
+		{n: "no shards"},
+		{
+			n:          "overlapping shards",
+			sketches:   []estimator.Sketch{sketch("cpu,host=a", "cpu,host=b"), sketch("cpu,host=b", "cpu,host=c")},
+			tombstones: []estimator.Sketch{sketch(), sketch()},
+			exp:        3,
+		},
+		{
+			n:          "tombstones",
+			sketches:   []estimator.Sketch{sketch("cpu,host=a", "cpu,host=b"), sketch("cpu,host=c")},
+			tombstones: []estimator.Sketch{sketch("cpu,host=a"), sketch()},
+			exp:        2,
+		},
+		{
+			n:          "more tombstones than series",
+			sketches:   []estimator.Sketch{sketch("cpu,host=a")},
+			tombstones: []estimator.Sketch{sketch("cpu,host=a", "cpu,host=b")},
+			exp:        0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			var before []uint64
+			for _, s := range tc.sketches {
+				before = append(before, s.Count())
+			}
+
+			got, err := estimateCardinality(tc.sketches, tc.tombstones)
+			if err != nil {
+				t.Fatal("estimateCardinality", err)
+			}
+			if got != tc.exp {
+				t.Errorf("unexpected cardinality: got %d, exp %d", got, tc.exp)
+			}
+
+			for i, s := range tc.sketches {
+				if s.Count() != before[i] {
+					t.Errorf("sketch %d modified", i)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_validateArgs(t *testing.T) {
+	cases := []struct {
+		n        string
+		database string
+		db, rp   string
+		err      error
+	}{
+		{n: "default retention policy", database: "db0", db: "db0", rp: "autogen"},
+		{n: "retention policy", database: "db0/autogen", db: "db0", rp: "autogen"},
+		{n: "missing database", database: "db1", err: ErrDatabaseNotFound},
+		{n: "missing database and retention policy", database: "db1/autogen", err: ErrDatabaseNotFound},
+		{n: "missing retention policy", database: "db0/rp1", err: ErrRetentionPolicyNotFound},
+	}
+
+	s := NewStore()
+	s.MetaClient = newMetaClient()
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			db, rp, start, end, err := s.validateArgs(context.Background(), tc.database, 0, 0)
+			if err != tc.err {
+				t.Fatalf("unexpected error; got %v, exp %v", err, tc.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if db != tc.db || rp != tc.rp {
+				t.Errorf("unexpected database; got %s/%s, exp %s/%s", db, rp, tc.db, tc.rp)
+			}
+			if start != models.MinNanoTime || end != models.MaxNanoTime {
+				t.Errorf("unexpected time range; got [%d, %d], exp [%d, %d]", start, end, models.MinNanoTime, models.MaxNanoTime)
+			}
+		})
+	}
+}
+
+func TestStore_Read_GroupKeys(t *testing.T) {
+	ts, closeStore := mustOpenTSDBStore(t, `
+cpu,host=a v=1 10
+cpu,host=b v=2 20
+mem,host=a,region=west v=3 30
+`)
+	defer closeStore()
+
+	cpuOnly := &Predicate{Root: &Node{
+		NodeType: NodeTypeComparisonExpression,
+		Value:    &Node_Comparison_{Comparison: ComparisonEqual},
+		Children: []*Node{
+			{NodeType: NodeTypeTagRef, Value: &Node_TagRefValue{TagRefValue: "_measurement"}},
+			{NodeType: NodeTypeLiteral, Value: &Node_StringValue{StringValue: "cpu"}},
+		},
+	}}
+
+	cases := []struct {
+		n      string
+		reject bool
+		keys   []string
+		pred   *Predicate
+		series int
+		err    string
+	}{
+		{n: "known key", keys: []string{"host"}, series: 3},
+		{n: "unknown key is an empty group", keys: []string{"dc"}, series: 3},
+		{n: "reject known key", reject: true, keys: []string{"host"}, series: 3},
+		{n: "reject unknown key", reject: true, keys: []string{"dc", "host"}, err: "unknown group keys: dc"},
+		{n: "reject key of another measurement", reject: true, keys: []string{"region"}, series: 3},
+		{n: "reject key not in predicate measurements", reject: true, keys: []string{"region"}, pred: cpuOnly, err: "unknown group keys: region"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			mc := newMetaClient()
+			mc.groups = []meta.ShardGroupInfo{{ID: 1, StartTime: time.Unix(0, 0), EndTime: time.Unix(0, 100), Shards: []meta.ShardInfo{{ID: 1}}}}
+
+			s := NewStore()
+			s.TSDBStore = ts
+			s.MetaClient = mc
+			s.RejectUnknownGroupKeys = tc.reject
+
+			rs, err := s.Read(context.Background(), &ReadRequest{Database: "db0", Grouping: tc.keys, Predicate: tc.pred})
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error; got %v, exp %s", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Read", err)
+			}
+			if rs == nil {
+				t.Fatal("expected ResultSet")
+			}
+			defer rs.Close()
+
+			var n int
+			for rs.Next() {
+				n++
+			}
+			if n != tc.series {
+				t.Errorf("unexpected number of series; got %d, exp %d", n, tc.series)
+			}
+		})
+	}
+}
+
+func TestStore_RequireFullCoverage(t *testing.T) {
+	ts, closeStore := mustOpenTSDBStore(t, `cpu,host=a v=1 10`)
+	defer closeStore()
+
+	mc := newMetaClient()
+	mc.groups = []meta.ShardGroupInfo{{ID: 1, StartTime: time.Unix(0, 0), EndTime: time.Unix(0, 100), Shards: []meta.ShardInfo{{ID: 1}}}}
+
+	s := NewStore()
+	s.TSDBStore = ts
+	s.MetaClient = mc
+	s.RequireFullCoverage = true
+
+	ctx := context.Background()
+	read := func(start, end int64) func() error {
+		return func() error {
+			rs, err := s.Read(ctx, &ReadRequest{Database: "db0", TimestampRange: TimestampRange{Start: start, End: end}})
+			if rs != nil {
+				rs.Close()
+			}
+			return err
+		}
+	}
+
+	cases := []struct {
+		n   string
+		fn  func() error
+		err string
+	}{
+		{n: "unbounded", fn: read(0, 0)},
+		{n: "open end", fn: read(10, 0)},
+		{n: "open start", fn: read(0, 200)},
+		{n: "covered", fn: read(10, 90)},
+		{n: "gap", fn: read(10, 200), err: "shard groups do not cover time range: gap from 1970-01-01T00:00:00.0000001Z to 1970-01-01T00:00:00.0000002Z"},
+		{n: "ReadFieldKeys", fn: func() error {
+			_, err := s.ReadFieldKeys(ctx, &ReadFieldKeysRequest{Database: "db0", TimestampRange: TimestampRange{Start: 10, End: 200}})
+			return err
+		}},
+		{n: "ReadMeasurementNames", fn: func() error {
+			_, err := s.ReadMeasurementNames(ctx, &ReadMeasurementNamesRequest{Database: "db0", TimestampRange: TimestampRange{Start: 10, End: 200}})
+			return err
+		}},
+		{n: "SeriesCardinality", fn: func() error {
+			_, err := s.SeriesCardinality(ctx, &CardinalityRequest{Database: "db0", TimestampRange: TimestampRange{Start: 10, End: 200}})
+			return err
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			err := tc.fn()
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tc.err {
+				t.Errorf("unexpected error: got %q, exp %q", got, tc.err)
+			}
+		})
+	}
+}
+
+func TestStore_TagKeySeriesCounts(t *testing.T) {
+	ts, closeStore := mustOpenTSDBStore(t, `
+cpu,host=a v=1 10
+cpu,host=b,region=west v=2 20
+`, `
+cpu,host=a v=3 110
+mem,region=east v=4 120
+`)
+	defer closeStore()
+
+	cpuOnly := &Predicate{Root: &Node{
+		NodeType: NodeTypeComparisonExpression,
+		Value:    &Node_Comparison_{Comparison: ComparisonEqual},
+		Children: []*Node{
+			{NodeType: NodeTypeTagRef, Value: &Node_TagRefValue{TagRefValue: "_measurement"}},
+			{NodeType: NodeTypeLiteral, Value: &Node_StringValue{StringValue: "cpu"}},
+		},
+	}}
+
+	mc := newMetaClient()
+	mc.groups = []meta.ShardGroupInfo{
+		{ID: 1, StartTime: time.Unix(0, 0), EndTime: time.Unix(0, 100), Shards: []meta.ShardInfo{{ID: 1}}},
+		{ID: 2, StartTime: time.Unix(0, 100), EndTime: time.Unix(0, 200), Shards: []meta.ShardInfo{{ID: 2}}},
+	}
+
+	s := NewStore()
+	s.TSDBStore = ts
+	s.MetaClient = mc
+
+	cases := []struct {
+		n    string
+		pred *Predicate
+		exp  map[string]uint64
+	}{
+		{n: "series in both shards are counted once", exp: map[string]uint64{"host": 2, "region": 2}},
+		{n: "predicate", pred: cpuOnly, exp: map[string]uint64{"host": 2, "region": 1}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got, err := s.TagKeySeriesCounts(context.Background(), &TagKeysRequest{Database: "db0", Predicate: tc.pred})
+			if err != nil {
+				t.Fatal("TagKeySeriesCounts", err)
+			}
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected counts; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestStore_TagKeyFirstSeen(t *testing.T) {
+	ts, closeStore := mustOpenTSDBStore(t, `
+cpu,host=a v=1 10
+`, `
+cpu,host=a,region=west v=2 110
+`)
+	defer closeStore()
+
+	mc := newMetaClient()
+	mc.groups = []meta.ShardGroupInfo{
+		{ID: 2, StartTime: time.Unix(0, 100), EndTime: time.Unix(0, 200), Shards: []meta.ShardInfo{{ID: 2}}},
+		{ID: 1, StartTime: time.Unix(0, 0), EndTime: time.Unix(0, 100), Shards: []meta.ShardInfo{{ID: 1}}},
+	}
+
+	s := NewStore()
+	s.TSDBStore = ts
+	s.MetaClient = mc
+
+	got, err := s.TagKeyFirstSeen(context.Background(), &TagKeysRequest{Database: "db0"})
+	if err != nil {
+		t.Fatal("TagKeyFirstSeen", err)
+	}
+
+	exp := map[string]int64{"host": 0, "region": 100}
+	if !cmp.Equal(got, exp) {
+		t.Errorf("unexpected times; -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+}
+
+func TestFirstSeen(t *testing.T) {
+	cases := []struct {
+		n      string
+		groups []groupTagKeys
+		exp    map[string]int64
+	}{
+		{n: "no groups", exp: map[string]int64{}},
+		{
+			n: "earliest group",
+			groups: []groupTagKeys{
+				{start: 10, keys: []string{"host"}},
+				{start: 20, keys: []string{"host", "region"}},
+			},
+			exp: map[string]int64{"host": 10, "region": 20},
+		},
+		{
+			n: "groups out of order",
+			groups: []groupTagKeys{
+				{start: 30, keys: []string{"host", "region"}},
+				{start: 10, keys: []string{"region"}},
+				{start: 20, keys: []string{"host"}},
+			},
+			exp: map[string]int64{"host": 20, "region": 10},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got := firstSeen(tc.groups)
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected times; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestStore_ReadTagKeys(t *testing.T) {
+	ts, closeStore := mustOpenTSDBStore(t, `
+cpu,host=a v=1 10
+`, `
+cpu,region=west v=2 110
+`)
+	defer closeStore()
+
+	mc := newMetaClient()
+	mc.groups = []meta.ShardGroupInfo{
+		{ID: 1, StartTime: time.Unix(0, 0), EndTime: time.Unix(0, 100), Shards: []meta.ShardInfo{{ID: 1}}},
+		{ID: 2, StartTime: time.Unix(0, 100), EndTime: time.Unix(0, 200), Shards: []meta.ShardInfo{{ID: 2}}},
+	}
+
+	s := NewStore()
+	s.TSDBStore = ts
+	s.MetaClient = mc
+
+	cases := []struct {
+		n       string
+		recency bool
+		exp     []string
+	}{
+		{n: "by key", exp: []string{"host", "region"}},
+		{n: "by recency", recency: true, exp: []string{"region", "host"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got, err := s.ReadTagKeys(context.Background(), &TagKeysRequest{Database: "db0", SortByRecency: tc.recency})
+			if err != nil {
+				t.Fatal("ReadTagKeys", err)
+			}
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected keys; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestSortByRecency(t *testing.T) {
+	cases := []struct {
+		n      string
+		groups []groupTagKeys
+		exp    []string
+	}{
+		{n: "no groups"},
+		{
+			n: "newest first",
+			groups: []groupTagKeys{
+				{start: 10, keys: []string{"host", "region"}},
+				{start: 20, keys: []string{"region"}},
+				{start: 30, keys: []string{"dc"}},
+			},
+			exp: []string{"dc", "region", "host"},
+		},
+		{
+			n: "latest group wins regardless of order",
+			groups: []groupTagKeys{
+				{start: 30, keys: []string{"host"}},
+				{start: 20, keys: []string{"region"}},
+				{start: 10, keys: []string{"host", "region"}},
+			},
+			exp: []string{"host", "region"},
+		},
+		{
+			n: "ties sorted by key",
+			groups: []groupTagKeys{
+				{start: 10, keys: []string{"region", "host", "dc"}},
+			},
+			exp: []string{"dc", "host", "region"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got := sortByRecency(tc.groups)
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected keys; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestCountTagKeys(t *testing.T) {
+	row := func(key string) tsdb.SeriesCursorRow {
+		tags := models.ParseTags([]byte(key))
+		return tsdb.SeriesCursorRow{Name: []byte(strings.SplitN(key, ",", 2)[0]), Tags: tags}
+	}
+
+	cases := []struct {
+		n    string
+		rows []tsdb.SeriesCursorRow
+		exp  map[string]uint64
+	}{
+		{n: "no series", exp: map[string]uint64{}},
+		{n: "no tags", rows: []tsdb.SeriesCursorRow{row("cpu")}, exp: map[string]uint64{}},
+		{
+			n:    "several measurements",
+			rows: []tsdb.SeriesCursorRow{row("cpu,host=a"), row("cpu,host=b,region=west"), row("mem,region=east")},
+			exp:  map[string]uint64{"host": 2, "region": 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			got, err := countTagKeys(&floatIterator{Points: tc.rows})
+			if err != nil {
+				t.Fatal("countTagKeys", err)
+			}
+			if !cmp.Equal(got, tc.exp) {
+				t.Errorf("unexpected counts; -got/+exp\n%s", cmp.Diff(got, tc.exp))
+			}
+		})
+	}
+}
+
+func TestStore_Metrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	s := NewStore()
+	s.MetaClient = newMetaClient()
+	s.WithMetrics(reg)
+
+	if _, err := s.Read(context.Background(), &ReadRequest{Database: "db0"}); err != nil {
+		t.Fatal("Read", err)
+	}
+	if _, err := s.Read(context.Background(), &ReadRequest{Database: "db1"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	got := gatherReadMetrics(t, reg)
+	exp := map[string]float64{
+		"storage_reads_requests_total":   2,
+		"storage_reads_errors_total":     1,
+		"storage_reads_duration_seconds": 2,
+		"storage_reads_shards_scanned":   0,
+	}
+	if !cmp.Equal(got, exp) {
+		t.Errorf("unexpected metrics; -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+}
+
+// gatherReadMetrics returns the value of each metric in reg, which must only
+// hold metrics of Read. Histograms are reported by their sample count.
+func gatherReadMetrics(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal("Gather", err)
+	}
+
+	got := make(map[string]float64)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			if len(m.GetLabel()) != 1 || m.GetLabel()[0].GetValue() != methodRead {
+				t.Fatalf("unexpected labels for %s: %v", mf.GetName(), m.GetLabel())
+			}
+
+			switch {
+			case m.Counter != nil:
+				got[mf.GetName()] = m.GetCounter().GetValue()
+			case m.Histogram != nil:
+				got[mf.GetName()] = float64(m.GetHistogram().GetSampleCount())
+			case m.Gauge != nil:
+				got[mf.GetName()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	return got
+}
+
+// metaClient is a StorageMetaClient with a single database. Every call to
+// ShardGroupsByTimeRange returns groups, regardless of the time range.
+type metaClient struct {
+	db     *meta.DatabaseInfo
+	groups []meta.ShardGroupInfo
+}
+
+// newMetaClient returns a metaClient with the database db0 and its default
+// retention policy autogen.
+func newMetaClient() *metaClient {
+	return &metaClient{db: &meta.DatabaseInfo{
+		Name:                   "db0",
+		DefaultRetentionPolicy: "autogen",
+		RetentionPolicies:      []meta.RetentionPolicyInfo{{Name: "autogen"}},
+	}}
+}
+
+func (c *metaClient) Database(name string) *meta.DatabaseInfo {
+	if c.db == nil || c.db.Name != name {
+		return nil
+	}
+	return c.db
+}
+
+func (c *metaClient) ShardGroupsByTimeRange(database, policy string, min, max time.Time) ([]meta.ShardGroupInfo, error) {
+	return append([]meta.ShardGroupInfo(nil), c.groups...), nil
+}
+
+func TestStore_Read_Tracing(t *testing.T) {
+	t.Run("read", func(t *testing.T) {
+		tracer := mocktracer.New()
+		parent := tracer.StartSpan("storage.read")
+		ctx := opentracing.ContextWithSpan(context.Background(), parent)
+
+		s := NewStore()
+		s.MetaClient = newMetaClient()
+
+		req := &ReadRequest{Database: "db0", TimestampRange: TimestampRange{Start: 10, End: 20}}
+		if _, err := s.Read(ctx, req); err != nil {
+			t.Fatal("Read", err)
+		}
+		parent.Finish()
+
+		names := make(map[int]string)
+		for _, sp := range tracer.FinishedSpans() {
+			names[sp.SpanContext.SpanID] = sp.OperationName
+		}
+
+		var got []string
+		var tags map[string]interface{}
+		for _, sp := range tracer.FinishedSpans() {
+			got = append(got, sp.OperationName+" < "+names[sp.ParentID])
+			if sp.OperationName == "storage.Read" {
+				tags = sp.Tags()
+			}
+		}
+
+		exp := []string{
+			"storage.validateArgs < storage.Read",
+			"storage.findShardIDs < storage.Read",
+			"storage.Read < storage.read",
+			"storage.read < ",
+		}
+		if !cmp.Equal(got, exp) {
+			t.Errorf("unexpected spans; -got/+exp\n%s", cmp.Diff(got, exp))
+		}
+
+		expTags := map[string]interface{}{
+			"start":       int64(10),
+			"end":         int64(20),
+			"shard_count": 0,
+		}
+		if !cmp.Equal(tags, expTags) {
+			t.Errorf("unexpected tags; -got/+exp\n%s", cmp.Diff(tags, expTags))
+		}
+	})
+
+	t.Run("series count", func(t *testing.T) {
+		tracer := mocktracer.New()
+		rs := &ResultSet{
+			cur:  &sliceSeriesCursor{rows: make([]seriesRow, 2)},
+			span: tracer.StartSpan("storage.Read"),
+		}
+		for rs.Next() {
+		}
+		rs.Close()
+
+		spans := tracer.FinishedSpans()
+		if len(spans) != 1 {
+			t.Fatalf("unexpected number of spans; got %d, exp 1", len(spans))
+		}
+		if got := spans[0].Tag("series_count"); got != 2 {
+			t.Errorf("unexpected series_count; got %v, exp 2", got)
+		}
+	})
+}
+
+// mustOpenTSDBStore returns an open tsdb.Store with a shard in db0/autogen for
+// each element of shards, with IDs 1, 2 and so on, containing the points in
+// that element. The returned function closes and removes the store.
+func mustOpenTSDBStore(t *testing.T, shards ...string) (*tsdb.Store, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "storage-store-")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := tsdb.NewStore(dir)
+	s.EngineOptions.Config.WALDir = filepath.Join(dir, "wal")
+	closeStore := func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+
+	if err := s.Open(); err != nil {
+		closeStore()
+		t.Fatal("Open", err)
+	}
+	for i, lines := range shards {
+		id := uint64(i + 1)
+		if err := s.CreateShard("db0", "autogen", id, true); err != nil {
+			closeStore()
+			t.Fatal("CreateShard", err)
+		}
+
+		points, err := models.ParsePointsString(strings.TrimSpace(lines))
+		if err != nil {
+			closeStore()
+			t.Fatal("ParsePointsString", err)
+		}
+		if err := s.WriteToShard(id, points); err != nil {
+			closeStore()
+			t.Fatal("WriteToShard", err)
+		}
+	}
+
+	return s, closeStore
+}